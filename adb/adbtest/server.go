@@ -0,0 +1,491 @@
+package adbtest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultVersion = 41
+
+type Device struct {
+	Serial  string
+	State   string
+	Product string
+	Model   string
+	Device  string
+}
+
+type File struct {
+	Data  []byte
+	Mode  os.FileMode
+	MTime time.Time
+}
+
+type ShellHandler func(serial, cmd string, conn net.Conn)
+
+type Server struct {
+	Version int
+
+	mutex    sync.Mutex
+	ln       net.Listener
+	wg       sync.WaitGroup
+	devices  []Device
+	forwards map[string]map[string]string
+	reverses map[string]map[string]string
+	files    map[string]map[string]File
+	requests []string
+	shell    ShellHandler
+	nextPort int
+}
+
+func NewServer(devices ...Device) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	for i := range devices {
+		if devices[i].State == "" {
+			devices[i].State = "device"
+		}
+	}
+
+	s := &Server{
+		Version:  DefaultVersion,
+		ln:       ln,
+		devices:  devices,
+		forwards: make(map[string]map[string]string),
+		reverses: make(map[string]map[string]string),
+		files:    make(map[string]map[string]File),
+		nextPort: 27183,
+	}
+
+	s.wg.Add(1)
+
+	go s.serve()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string { return s.ln.Addr().String() }
+
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) SetShellHandler(h ShellHandler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.shell = h
+}
+
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) Forwards(serial string) map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return maps.Clone(s.forwards[serial])
+}
+
+func (s *Server) Reverses(serial string) map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return maps.Clone(s.reverses[serial])
+}
+
+func (s *Server) File(serial, path string) (File, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f, ok := s.files[serial][path]
+
+	return f, ok
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	req, err := readRequest(r)
+	if err != nil {
+		return
+	}
+
+	s.log(req)
+
+	switch {
+	case req == "host:version":
+		okay(conn, fmt.Sprintf("%04x", s.Version))
+	case req == "host:devices" || req == "host:devices-l":
+		okay(conn, s.deviceList(req == "host:devices-l"))
+	case req == "host:transport-any" || strings.HasPrefix(req, "host:transport:"):
+		serial, ok := s.lookup(strings.TrimPrefix(req, "host:transport:"))
+		if req == "host:transport-any" {
+			serial, ok = s.lookup("")
+		}
+
+		if !ok {
+			fail(conn, "device not found")
+
+			return
+		}
+
+		_, _ = io.WriteString(conn, "OKAY")
+
+		s.handleDevice(serial, conn, r)
+	case strings.HasPrefix(req, "host:") || strings.HasPrefix(req, "host-serial:"):
+		s.handleHostSerial(conn, req)
+	default:
+		fail(conn, "unknown host service")
+	}
+}
+
+func (s *Server) handleHostSerial(conn net.Conn, req string) {
+	var serial, cmd string
+
+	if rest, ok := strings.CutPrefix(req, "host-serial:"); ok {
+		i := strings.LastIndex(rest, ":forward:")
+		if i < 0 {
+			i = strings.LastIndex(rest, ":killforward:")
+		}
+
+		if i < 0 {
+			fail(conn, "unknown host service")
+
+			return
+		}
+
+		serial, cmd = rest[:i], rest[i+1:]
+	} else {
+		cmd = strings.TrimPrefix(req, "host:")
+	}
+
+	serial, ok := s.lookup(serial)
+	if !ok {
+		fail(conn, "device not found")
+
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case strings.HasPrefix(cmd, "forward:"):
+		local, remote, ok := strings.Cut(strings.TrimPrefix(cmd, "forward:"), ";")
+		if !ok {
+			fail(conn, "malformed forward spec")
+
+			return
+		}
+
+		port := s.bind(s.forwards, serial, local, remote)
+
+		_, _ = io.WriteString(conn, "OKAYOKAY")
+
+		if local == "tcp:0" {
+			_, _ = fmt.Fprintf(conn, "%04x%s", len(port), port)
+		}
+	case strings.HasPrefix(cmd, "killforward:"):
+		local := strings.TrimPrefix(cmd, "killforward:")
+
+		if _, ok := s.forwards[serial][local]; !ok {
+			fail(conn, "listener '"+local+"' not found")
+
+			return
+		}
+
+		delete(s.forwards[serial], local)
+
+		_, _ = io.WriteString(conn, "OKAYOKAY")
+	default:
+		fail(conn, "unknown host service")
+	}
+}
+
+func (s *Server) handleDevice(serial string, conn net.Conn, r *bufio.Reader) {
+	req, err := readRequest(r)
+	if err != nil {
+		return
+	}
+
+	s.log(req)
+
+	switch {
+	case strings.HasPrefix(req, "shell:"):
+		s.mutex.Lock()
+		h := s.shell
+		s.mutex.Unlock()
+
+		_, _ = io.WriteString(conn, "OKAY")
+
+		if h != nil {
+			h(serial, strings.TrimPrefix(req, "shell:"), conn)
+		}
+	case strings.HasPrefix(req, "reverse:forward:"):
+		remote, local, ok := strings.Cut(strings.TrimPrefix(req, "reverse:forward:"), ";")
+		if !ok {
+			fail(conn, "malformed reverse spec")
+
+			return
+		}
+
+		s.mutex.Lock()
+		port := s.bind(s.reverses, serial, remote, local)
+		s.mutex.Unlock()
+
+		_, _ = io.WriteString(conn, "OKAYOKAY")
+
+		if remote == "tcp:0" {
+			_, _ = fmt.Fprintf(conn, "%04x%s", len(port), port)
+		}
+	case strings.HasPrefix(req, "reverse:killforward:"):
+		remote := strings.TrimPrefix(req, "reverse:killforward:")
+
+		s.mutex.Lock()
+		_, ok := s.reverses[serial][remote]
+		delete(s.reverses[serial], remote)
+		s.mutex.Unlock()
+
+		if !ok {
+			fail(conn, "listener '"+remote+"' not found")
+
+			return
+		}
+
+		_, _ = io.WriteString(conn, "OKAYOKAY")
+	case req == "sync:":
+		_, _ = io.WriteString(conn, "OKAY")
+
+		s.handleSync(serial, conn, r)
+	default:
+		fail(conn, "unknown device service")
+	}
+}
+
+func (s *Server) handleSync(serial string, conn net.Conn, r *bufio.Reader) {
+	for {
+		id, _, payload, err := readSyncRequest(r)
+		if err != nil {
+			return
+		}
+
+		switch id {
+		case "SEND":
+			if err := s.receiveFile(serial, string(payload), r); err != nil {
+				syncFail(conn, err.Error())
+
+				return
+			}
+
+			_, _ = conn.Write([]byte("OKAY\x00\x00\x00\x00"))
+		case "QUIT":
+			return
+		default:
+			syncFail(conn, "unsupported sync request "+id)
+
+			return
+		}
+	}
+}
+
+func (s *Server) receiveFile(serial, spec string, r *bufio.Reader) error {
+	path, modeStr, ok := strings.Cut(spec, ",")
+	if !ok {
+		return errors.New("malformed SEND spec")
+	}
+
+	mode, err := strconv.ParseUint(modeStr, 10, 32)
+	if err != nil {
+		return fmt.Errorf("bad mode: %w", err)
+	}
+
+	var data []byte
+
+	for {
+		id, arg, payload, err := readSyncRequest(r)
+		if err != nil {
+			return err
+		}
+
+		switch id {
+		case "DATA":
+			data = append(data, payload...)
+		case "DONE":
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			if s.files[serial] == nil {
+				s.files[serial] = make(map[string]File)
+			}
+
+			s.files[serial][path] = File{
+				Data:  data,
+				Mode:  os.FileMode(mode).Perm(),
+				MTime: time.Unix(int64(arg), 0),
+			}
+
+			return nil
+		default:
+			return fmt.Errorf("unexpected sync request %s", id)
+		}
+	}
+}
+
+func (s *Server) bind(table map[string]map[string]string, serial, from, to string) string {
+	port := ""
+
+	if from == "tcp:0" {
+		port = strconv.Itoa(s.nextPort)
+		from = "tcp:" + port
+		s.nextPort++
+	}
+
+	if table[serial] == nil {
+		table[serial] = make(map[string]string)
+	}
+
+	table[serial][from] = to
+
+	return port
+}
+
+func (s *Server) lookup(serial string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, d := range s.devices {
+		if serial == "" || d.Serial == serial {
+			return d.Serial, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *Server) deviceList(long bool) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var b strings.Builder
+
+	for i, d := range s.devices {
+		if !long {
+			fmt.Fprintf(&b, "%s\t%s\n", d.Serial, d.State)
+
+			continue
+		}
+
+		fmt.Fprintf(&b, "%-22s %s product:%s model:%s device:%s transport_id:%d\n",
+			d.Serial, d.State, d.Product, d.Model, d.Device, i+1)
+	}
+
+	return b.String()
+}
+
+func (s *Server) log(req string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, req)
+}
+
+func readRequest(r io.Reader) (string, error) {
+	hexLen := make([]byte, 4)
+
+	if _, err := io.ReadFull(r, hexLen); err != nil {
+		return "", err
+	}
+
+	n, err := strconv.ParseUint(string(hexLen), 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("bad length %q: %w", hexLen, err)
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+func readSyncRequest(r io.Reader) (string, uint32, []byte, error) {
+	hdr := make([]byte, 8)
+
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return "", 0, nil, err
+	}
+
+	id := string(hdr[:4])
+	arg := binary.LittleEndian.Uint32(hdr[4:])
+
+	if id == "DONE" {
+		return id, arg, nil, nil
+	}
+
+	buf := make([]byte, arg)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", 0, nil, err
+	}
+
+	return id, arg, buf, nil
+}
+
+func okay(w io.Writer, payload string) {
+	_, _ = fmt.Fprintf(w, "OKAY%04x%s", len(payload), payload)
+}
+
+func fail(w io.Writer, msg string) {
+	_, _ = fmt.Fprintf(w, "FAIL%04x%s", len(msg), msg)
+}
+
+func syncFail(w io.Writer, msg string) {
+	hdr := make([]byte, 8)
+	copy(hdr, "FAIL")
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(len(msg)))
+
+	_, _ = w.Write(append(hdr, msg...))
+}
@@ -0,0 +1,184 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultAddr = "127.0.0.1:5037"
+
+type Client struct {
+	addr    string
+	timeout time.Duration
+}
+
+type DeviceInfo struct {
+	Serial      string
+	State       string
+	Product     string
+	Model       string
+	Device      string
+	TransportID string
+}
+
+func NewClient(addr string) *Client {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	return &Client{
+		addr:    addr,
+		timeout: 5 * time.Second,
+	}
+}
+
+func (c *Client) Addr() string { return c.addr }
+
+func (c *Client) Version(ctx context.Context) (int, error) {
+	resp, err := c.query(ctx, "host:version")
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseUint(resp, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad version %q", ErrProtocol, resp)
+	}
+
+	return int(v), nil
+}
+
+func (c *Client) Devices(ctx context.Context) ([]DeviceInfo, error) {
+	resp, err := c.query(ctx, "host:devices-l")
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []DeviceInfo
+
+	for _, line := range strings.Split(resp, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		dev := DeviceInfo{Serial: fields[0]}
+		state := []string{}
+
+		for _, f := range fields[1:] {
+			key, value, ok := strings.Cut(f, ":")
+			if !ok {
+				state = append(state, f)
+
+				continue
+			}
+
+			switch key {
+			case "product":
+				dev.Product = value
+			case "model":
+				dev.Model = value
+			case "device":
+				dev.Device = value
+			case "transport_id":
+				dev.TransportID = value
+			default:
+				state = append(state, f)
+			}
+		}
+
+		dev.State = strings.Join(state, " ")
+		devices = append(devices, dev)
+	}
+
+	return devices, nil
+}
+
+func (c *Client) Device(serial string) *Device {
+	return &Device{
+		client: c,
+		serial: serial,
+	}
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	conn, err := (&net.Dialer{Timeout: c.timeout}).DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("adb dial: %w", err)
+	}
+
+	return conn, nil
+}
+
+func (c *Client) request(ctx context.Context, req string) (net.Conn, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := roundTrip(ctx, conn, req); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *Client) query(ctx context.Context, req string) (string, error) {
+	conn, err := c.request(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	defer conn.Close()
+
+	return withContext(ctx, conn, func() (string, error) {
+		return readString(conn)
+	})
+}
+
+func roundTrip(ctx context.Context, conn net.Conn, req string) error {
+	_, err := withContext(ctx, conn, func() (struct{}, error) {
+		if err := writeRequest(conn, req); err != nil {
+			return struct{}{}, err
+		}
+
+		if err := readStatus(conn); err != nil {
+			return struct{}{}, fmt.Errorf("%s: %w", req, err)
+		}
+
+		return struct{}{}, nil
+	})
+
+	return err
+}
+
+func withContext[T any](ctx context.Context, conn net.Conn, fn func() (T, error)) (T, error) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+
+	v, err := fn()
+
+	if !stop() {
+		_ = conn.SetDeadline(time.Time{})
+
+		if err != nil {
+			err = ctx.Err()
+		}
+	}
+
+	return v, err
+}
+
+func readAll(ctx context.Context, conn net.Conn) ([]byte, error) {
+	return withContext(ctx, conn, func() ([]byte, error) {
+		return io.ReadAll(conn)
+	})
+}
@@ -0,0 +1,236 @@
+package adb_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/merzzzl/scrcpy-go/adb"
+	"github.com/merzzzl/scrcpy-go/adb/adbtest"
+)
+
+func testServer(t *testing.T, devices ...adbtest.Device) (*adbtest.Server, *adb.Client, context.Context) {
+	t.Helper()
+
+	srv, err := adbtest.NewServer(devices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = srv.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	return srv, adb.NewClient(srv.Addr()), ctx
+}
+
+func TestVersion(t *testing.T) {
+	srv, c, ctx := testServer(t)
+	srv.Version = 0x29
+
+	v, err := c.Version(ctx)
+	if err != nil {
+		t.Fatalf("Version: %v", err)
+	}
+
+	if v != 0x29 {
+		t.Fatalf("Version = %d, want %d", v, 0x29)
+	}
+}
+
+func TestDevices(t *testing.T) {
+	_, c, ctx := testServer(t,
+		adbtest.Device{Serial: "emulator-5554", Product: "sdk_gphone64", Model: "Pixel_7", Device: "emu64a"},
+		adbtest.Device{Serial: "R58M12345", State: "unauthorized"},
+	)
+
+	devices, err := c.Devices(ctx)
+	if err != nil {
+		t.Fatalf("Devices: %v", err)
+	}
+
+	want := []adb.DeviceInfo{
+		{Serial: "emulator-5554", State: "device", Product: "sdk_gphone64", Model: "Pixel_7", Device: "emu64a", TransportID: "1"},
+		{Serial: "R58M12345", State: "unauthorized", TransportID: "2"},
+	}
+
+	if !reflect.DeepEqual(devices, want) {
+		t.Fatalf("Devices = %+v, want %+v", devices, want)
+	}
+}
+
+func TestDevicesEmpty(t *testing.T) {
+	_, c, ctx := testServer(t)
+
+	devices, err := c.Devices(ctx)
+	if err != nil {
+		t.Fatalf("Devices: %v", err)
+	}
+
+	if len(devices) != 0 {
+		t.Fatalf("Devices = %+v, want none", devices)
+	}
+}
+
+func TestForward(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "a"}, adbtest.Device{Serial: "b"})
+	dev := c.Device("b")
+
+	local, err := dev.Forward(ctx, "tcp:0", "localabstract:scrcpy")
+	if err != nil {
+		t.Fatalf("Forward(tcp:0): %v", err)
+	}
+
+	if local != "tcp:27183" {
+		t.Fatalf("Forward(tcp:0) = %q, want %q", local, "tcp:27183")
+	}
+
+	if local, err := dev.Forward(ctx, "tcp:5000", "localabstract:other"); err != nil || local != "" {
+		t.Fatalf("Forward(tcp:5000) = %q, %v, want empty", local, err)
+	}
+
+	want := map[string]string{"tcp:27183": "localabstract:scrcpy", "tcp:5000": "localabstract:other"}
+	if got := srv.Forwards("b"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("forwards = %v, want %v", got, want)
+	}
+
+	if got := srv.Forwards("a"); len(got) != 0 {
+		t.Fatalf("forwards on other device = %v", got)
+	}
+
+	if err := dev.KillForward(ctx, local); err != nil {
+		t.Fatalf("KillForward: %v", err)
+	}
+
+	if err := dev.KillForward(ctx, local); !errors.Is(err, adb.ErrFailed) {
+		t.Fatalf("second KillForward = %v, want ErrFailed", err)
+	}
+
+	if got := srv.Forwards("b"); !reflect.DeepEqual(got, map[string]string{"tcp:5000": "localabstract:other"}) {
+		t.Fatalf("forwards after kill = %v", got)
+	}
+}
+
+func TestForwardAnyDevice(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "only"})
+
+	local, err := c.Device("").Forward(ctx, "tcp:0", "localabstract:scrcpy")
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+
+	if _, ok := srv.Forwards("only")[local]; !ok {
+		t.Fatalf("forward %s not registered: %v", local, srv.Forwards("only"))
+	}
+}
+
+func TestForwardUnknownDevice(t *testing.T) {
+	_, c, ctx := testServer(t, adbtest.Device{Serial: "a"})
+
+	if _, err := c.Device("missing").Forward(ctx, "tcp:0", "localabstract:scrcpy"); !errors.Is(err, adb.ErrFailed) {
+		t.Fatalf("Forward = %v, want ErrFailed", err)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "a"})
+	dev := c.Device("a")
+
+	remote, err := dev.Reverse(ctx, "localabstract:scrcpy", "tcp:27183")
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	if remote != "" {
+		t.Fatalf("Reverse = %q, want empty", remote)
+	}
+
+	remote, err = dev.Reverse(ctx, "tcp:0", "tcp:8080")
+	if err != nil {
+		t.Fatalf("Reverse(tcp:0): %v", err)
+	}
+
+	if remote != "tcp:27183" {
+		t.Fatalf("Reverse(tcp:0) = %q, want %q", remote, "tcp:27183")
+	}
+
+	want := map[string]string{"localabstract:scrcpy": "tcp:27183", "tcp:27183": "tcp:8080"}
+	if got := srv.Reverses("a"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("reverses = %v, want %v", got, want)
+	}
+
+	if err := dev.KillReverse(ctx, "localabstract:scrcpy"); err != nil {
+		t.Fatalf("KillReverse: %v", err)
+	}
+
+	if err := dev.KillReverse(ctx, "localabstract:scrcpy"); !errors.Is(err, adb.ErrFailed) {
+		t.Fatalf("second KillReverse = %v, want ErrFailed", err)
+	}
+
+	if got := srv.Reverses("a"); !reflect.DeepEqual(got, map[string]string{"tcp:27183": "tcp:8080"}) {
+		t.Fatalf("reverses after kill = %v", got)
+	}
+}
+
+func TestPush(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "a"})
+
+	data := make([]byte, 200*1024+17)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	mtime := time.Unix(1700000000, 0)
+
+	if err := c.Device("a").Push(ctx, bytes.NewReader(data), "/data/local/tmp/scrcpy-server.jar", 0o644, mtime); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	f, ok := srv.File("a", "/data/local/tmp/scrcpy-server.jar")
+	if !ok {
+		t.Fatal("file not pushed")
+	}
+
+	if !bytes.Equal(f.Data, data) {
+		t.Fatalf("pushed %d bytes, want %d", len(f.Data), len(data))
+	}
+
+	if f.Mode != 0o644 {
+		t.Fatalf("mode = %v, want %v", f.Mode, os.FileMode(0o644))
+	}
+
+	if !f.MTime.Equal(mtime) {
+		t.Fatalf("mtime = %v, want %v", f.MTime, mtime)
+	}
+}
+
+func TestPushEmpty(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "a"})
+
+	if err := c.Device("a").Push(ctx, bytes.NewReader(nil), "/sdcard/empty", os.ModeDir|0o755, time.Unix(1, 0)); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	f, ok := srv.File("a", "/sdcard/empty")
+	if !ok || len(f.Data) != 0 || f.Mode != 0o755 {
+		t.Fatalf("file = %+v, %t", f, ok)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	srv, c, ctx := testServer(t, adbtest.Device{Serial: "a"})
+
+	if _, err := c.Device("a").Reverse(ctx, "localabstract:scrcpy", "tcp:27183"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"host:transport:a", "reverse:forward:localabstract:scrcpy;tcp:27183"}
+	if got := srv.Requests(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("requests = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,194 @@
+package adb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const syncDataMax = 64 * 1024
+
+type Device struct {
+	client *Client
+	serial string
+}
+
+func (d *Device) Serial() string { return d.serial }
+
+func (d *Device) Shell(ctx context.Context, cmd string) (net.Conn, error) {
+	return d.Open(ctx, "shell:"+cmd)
+}
+
+func (d *Device) RunShell(ctx context.Context, cmd string) ([]byte, error) {
+	conn, err := d.Shell(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	out, err := readAll(ctx, conn)
+	if err != nil {
+		return out, fmt.Errorf("read shell output: %w", err)
+	}
+
+	return out, nil
+}
+
+func (d *Device) Open(ctx context.Context, service string) (net.Conn, error) {
+	conn, err := d.client.request(ctx, d.transport())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := roundTrip(ctx, conn, service); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (d *Device) Forward(ctx context.Context, local, remote string) (string, error) {
+	return d.hostCommand(ctx, "forward:"+local+";"+remote, local == "tcp:0")
+}
+
+func (d *Device) KillForward(ctx context.Context, local string) error {
+	_, err := d.hostCommand(ctx, "killforward:"+local, false)
+
+	return err
+}
+
+func (d *Device) Reverse(ctx context.Context, remote, local string) (string, error) {
+	return d.deviceCommand(ctx, "reverse:forward:"+remote+";"+local, remote == "tcp:0")
+}
+
+func (d *Device) KillReverse(ctx context.Context, remote string) error {
+	_, err := d.deviceCommand(ctx, "reverse:killforward:"+remote, false)
+
+	return err
+}
+
+func (d *Device) Push(ctx context.Context, r io.Reader, remotePath string, mode os.FileMode, mtime time.Time) error {
+	conn, err := d.Open(ctx, "sync:")
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = withContext(ctx, conn, func() (struct{}, error) {
+		return struct{}{}, push(conn, r, remotePath, mode, mtime)
+	})
+	if err != nil {
+		return fmt.Errorf("push %s: %w", remotePath, err)
+	}
+
+	return nil
+}
+
+func (d *Device) transport() string {
+	if d.serial == "" {
+		return "host:transport-any"
+	}
+
+	return "host:transport:" + d.serial
+}
+
+func (d *Device) hostPrefix() string {
+	if d.serial == "" {
+		return "host:"
+	}
+
+	return "host-serial:" + d.serial + ":"
+}
+
+func (d *Device) hostCommand(ctx context.Context, cmd string, resolvePort bool) (string, error) {
+	conn, err := d.client.request(ctx, d.hostPrefix()+cmd)
+	if err != nil {
+		return "", err
+	}
+
+	defer conn.Close()
+
+	return finishCommand(ctx, conn, cmd, resolvePort)
+}
+
+func (d *Device) deviceCommand(ctx context.Context, cmd string, resolvePort bool) (string, error) {
+	conn, err := d.Open(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	defer conn.Close()
+
+	return finishCommand(ctx, conn, cmd, resolvePort)
+}
+
+func finishCommand(ctx context.Context, conn net.Conn, cmd string, resolvePort bool) (string, error) {
+	return withContext(ctx, conn, func() (string, error) {
+		if err := readStatus(conn); err != nil {
+			return "", fmt.Errorf("%s: %w", cmd, err)
+		}
+
+		if !resolvePort {
+			return "", nil
+		}
+
+		port, err := readString(conn)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", cmd, err)
+		}
+
+		return "tcp:" + strings.TrimSpace(port), nil
+	})
+}
+
+func push(conn net.Conn, r io.Reader, remotePath string, mode os.FileMode, mtime time.Time) error {
+	spec := remotePath + "," + strconv.FormatUint(uint64(mode.Perm())|0o100000, 10)
+
+	if err := writeSyncRequest(conn, "SEND", []byte(spec)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, syncDataMax)
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := writeSyncRequest(conn, "DATA", buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("read source: %w", err)
+		}
+	}
+
+	done := make([]byte, 8)
+	copy(done, "DONE")
+	binary.LittleEndian.PutUint32(done[4:], uint32(mtime.Unix()))
+
+	if _, err := conn.Write(done); err != nil {
+		return fmt.Errorf("write DONE: %w", err)
+	}
+
+	if err := readSyncStatus(conn); err != nil {
+		return err
+	}
+
+	return writeSyncRequest(conn, "QUIT", nil)
+}
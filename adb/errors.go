@@ -0,0 +1,9 @@
+package adb
+
+import "errors"
+
+var (
+	ErrFailed         = errors.New("adb request failed")
+	ErrProtocol       = errors.New("adb protocol error")
+	ErrRequestTooLong = errors.New("adb request exceeds 65535 bytes")
+)
@@ -0,0 +1,111 @@
+package adb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	statusOkay = "OKAY"
+	statusFail = "FAIL"
+	maxRequest = 0xffff
+)
+
+func writeRequest(w io.Writer, req string) error {
+	if len(req) > maxRequest {
+		return ErrRequestTooLong
+	}
+
+	if _, err := fmt.Fprintf(w, "%04x%s", len(req), req); err != nil {
+		return fmt.Errorf("write request: %w", err)
+	}
+
+	return nil
+}
+
+func readStatus(r io.Reader) error {
+	status := make([]byte, 4)
+
+	if _, err := io.ReadFull(r, status); err != nil {
+		return fmt.Errorf("read status: %w", err)
+	}
+
+	switch string(status) {
+	case statusOkay:
+		return nil
+	case statusFail:
+		msg, err := readString(r)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s", ErrFailed, msg)
+	default:
+		return fmt.Errorf("%w: unexpected status %q", ErrProtocol, status)
+	}
+}
+
+func readString(r io.Reader) (string, error) {
+	hexLen := make([]byte, 4)
+
+	if _, err := io.ReadFull(r, hexLen); err != nil {
+		return "", fmt.Errorf("read length: %w", err)
+	}
+
+	n, err := strconv.ParseUint(string(hexLen), 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("%w: bad length %q", ErrProtocol, hexLen)
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", fmt.Errorf("read payload: %w", err)
+	}
+
+	return string(buf), nil
+}
+
+func writeSyncRequest(w io.Writer, id string, payload []byte) error {
+	buf := make([]byte, 8, 8+len(payload))
+	copy(buf, id)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(payload)))
+	buf = append(buf, payload...)
+
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("write %s: %w", id, err)
+	}
+
+	return nil
+}
+
+func readSyncStatus(r io.Reader) error {
+	hdr := make([]byte, 8)
+
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return fmt.Errorf("read sync status: %w", err)
+	}
+
+	n := binary.LittleEndian.Uint32(hdr[4:])
+
+	switch string(hdr[:4]) {
+	case statusOkay:
+		return nil
+	case statusFail:
+		if n > maxRequest {
+			return fmt.Errorf("%w: sync message too long", ErrProtocol)
+		}
+
+		msg := make([]byte, n)
+
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return fmt.Errorf("read sync message: %w", err)
+		}
+
+		return fmt.Errorf("%w: %s", ErrFailed, msg)
+	default:
+		return fmt.Errorf("%w: unexpected sync status %q", ErrProtocol, hdr[:4])
+	}
+}
@@ -1,205 +1,436 @@
 package scrcpy
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/merzzzl/scrcpy-go/protocol"
 	"golang.org/x/sync/errgroup"
 )
 
 type VideoHandler func(io.Reader) error
+type AudioHandler func(io.Reader) error
 type ControlHandler func(context.Context, ControlMessage) error
 
 type ControlMessage struct {
 	Type    DeviceMessageType
 	Payload []byte
+	Message protocol.DeviceMessage
 }
 
 type Handshake struct {
-	DeviceName string
-	CodecID    uint32
-	Width      uint32
-	Height     uint32
+	DeviceName   string
+	CodecID      Codec
+	Width        uint32
+	Height       uint32
+	AudioCodecID Codec
+	Video        bool
+	Audio        bool
+	Control      bool
+	DeviceMeta   bool
+	VideoMeta    bool
+	AudioMeta    bool
 }
 
 type Client struct {
+	opts           DialOptions
 	handshake      Handshake
 	videoConn      net.Conn
+	audioConn      net.Conn
 	controlConn    net.Conn
 	videoHandler   VideoHandler
+	audioHandler   AudioHandler
+	videoPackets   PacketHandler
+	audioPackets   PacketHandler
 	controlHandler ControlHandler
+	onClipboard    func(text string)
+	onAckClipboard func(seq uint64)
+	onUhidOutput   func(id uint16, data []byte)
+	onResize       func(width, height uint32)
+	sendMutex      sync.Mutex
+	onSend         []*sendHook
+	closers        []io.Closer
+	writer         *controlWriter
+	waitMutex      sync.Mutex
+	clipWaiters    []*clipWaiter
+	ackWaiters     map[uint64]chan struct{}
+	clipSeq        atomic.Uint64
+	touchMutex     sync.Mutex
+	touches        []*TouchSession
+	pointerSeq     atomic.Uint64
+	uhidMutex      sync.Mutex
+	uhidDevices    map[uint16]*uhidDevice
+	frameSize      atomic.Uint64
 }
 
-func Dial(ctx context.Context, addr string) (*Client, error) {
-	dial := (&net.Dialer{Timeout: 5 * time.Second}).DialContext
+type sendHook struct {
+	fn func(msg protocol.ControlMessage)
+}
 
-	vConn, err := dial(ctx, "tcp", addr)
-	if err != nil {
-		return nil, fmt.Errorf("video dial: %w", err)
+type socket struct {
+	name string
+	conn *net.Conn
+}
+
+func Dial(ctx context.Context, addr string, opts ...DialOption) (*Client, error) {
+	o := newDialOptions(DefaultDialOptions(), opts)
+
+	if err := o.validate(); err != nil {
+		return nil, err
 	}
 
-	cConn, err := dial(ctx, "tcp", addr)
+	c, err := dialFirst(ctx, addr, o)
 	if err != nil {
-		_ = vConn.Close()
+		return nil, err
+	}
+
+	if err := c.dialRest(ctx, addr); err != nil {
+		_ = c.Close()
 
-		return nil, fmt.Errorf("control dial: %w", err)
+		return nil, err
 	}
 
-	if tcp, ok := cConn.(*net.TCPConn); ok {
-		_ = tcp.SetNoDelay(true)
+	return c, nil
+}
+
+func dialFirst(ctx context.Context, addr string, o DialOptions) (*Client, error) {
+	c := &Client{opts: o}
+	first := c.sockets()[0]
+
+	conn, err := (&net.Dialer{Timeout: o.DialTimeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s dial: %w", first.name, err)
 	}
 
-	if err := readExactly(vConn, dummyLen, nil); err != nil {
-		_ = vConn.Close()
+	*first.conn = conn
 
-		return nil, fmt.Errorf("handshake dummy: %w", err)
+	if o.SendDummyByte {
+		if err := readDummy(ctx, conn, o.HandshakeTimeout); err != nil {
+			_ = c.Close()
+
+			return nil, err
+		}
 	}
 
-	nameRaw := make([]byte, deviceNameLen)
+	return c, nil
+}
+
+func (c *Client) dialRest(ctx context.Context, addr string) error {
+	dialer := &net.Dialer{Timeout: c.opts.DialTimeout}
 
-	if err := readExactly(vConn, deviceNameLen, nameRaw); err != nil {
-		_ = vConn.Close()
+	for _, s := range c.sockets()[1:] {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("%s dial: %w", s.name, err)
+		}
 
-		return nil, fmt.Errorf("handshake name: %w", err)
+		*s.conn = conn
 	}
 
-	meta := make([]byte, videoHeaderLen)
+	return c.open(ctx)
+}
 
-	if err := readExactly(vConn, videoHeaderLen, meta); err != nil {
-		_ = vConn.Close()
+func readDummy(ctx context.Context, conn net.Conn, timeout time.Duration) error {
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
 
-		return nil, fmt.Errorf("handshake meta: %w", err)
+	if timeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
 	}
 
-	hs := Handshake{
-		DeviceName: string(nameRaw),
-		CodecID:    binary.BigEndian.Uint32(meta[:4]),
-		Width:      binary.BigEndian.Uint32(meta[4:8]),
-		Height:     binary.BigEndian.Uint32(meta[8:12]),
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+
+	if err := readExactly(conn, dummyLen, nil); err != nil {
+		return handshakeErr(ctx, "dummy", err)
 	}
 
-	return &Client{
-		videoConn:   vConn,
-		controlConn: cConn,
-		handshake:   hs,
-	}, nil
+	return nil
 }
 
-func (c *Client) SetVideoHandler(h VideoHandler) { c.videoHandler = h }
+func (c *Client) sockets() []socket {
+	var socks []socket
 
-func (c *Client) SetControlHandler(h ControlHandler) { c.controlHandler = h }
+	if c.opts.Video {
+		socks = append(socks, socket{name: "video", conn: &c.videoConn})
+	}
 
-func (c *Client) GetHandshake() Handshake { return c.handshake }
+	if c.opts.Audio {
+		socks = append(socks, socket{name: "audio", conn: &c.audioConn})
+	}
 
-func (c *Client) Serve(ctx context.Context) error {
-	eg, gctx := errgroup.WithContext(ctx)
-	gctx, cancel := context.WithCancel(gctx)
+	if c.opts.Control {
+		socks = append(socks, socket{name: "control", conn: &c.controlConn})
+	}
 
-	eg.Go(func() error {
-		defer cancel()
+	return socks
+}
 
-		return c.readVideo(gctx)
-	})
+func (c *Client) open(ctx context.Context) error {
+	if err := c.readHandshake(ctx); err != nil {
+		return err
+	}
 
-	eg.Go(func() error {
-		defer cancel()
+	c.setFrameSize(c.handshake.Width, c.handshake.Height)
 
-		return c.readControl(gctx)
-	})
+	if c.controlConn != nil {
+		c.writer = newControlWriter(c.controlConn, c.opts.WriteTimeout, c.opts.WriteBatch)
+	}
 
-	return eg.Wait()
+	return nil
 }
 
-func (c *Client) readVideo(ctx context.Context) error {
-	hdr := make([]byte, 12)
-	pr, pw := io.Pipe()
-	eg, gctx := errgroup.WithContext(ctx)
+func (c *Client) readHandshake(ctx context.Context) error {
+	socks := c.sockets()
+	first := *socks[0].conn
 
-	eg.Go(func() error {
-		if c.videoHandler == nil {
-			return nil
+	if tcp, ok := c.controlConn.(*net.TCPConn); ok {
+		_ = tcp.SetNoDelay(true)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		for _, s := range socks {
+			_ = (*s.conn).SetReadDeadline(time.Now())
 		}
+	})
+	defer stop()
 
-		if err := c.videoHandler(pr); err != nil {
-			return fmt.Errorf("video handler: %w", err)
+	if c.opts.HandshakeTimeout > 0 {
+		deadline := time.Now().Add(c.opts.HandshakeTimeout)
+
+		for _, s := range socks {
+			_ = (*s.conn).SetReadDeadline(deadline)
 		}
+	}
 
-		return nil
-	})
+	defer func() {
+		for _, s := range socks {
+			_ = (*s.conn).SetReadDeadline(time.Time{})
+		}
+	}()
 
-	eg.Go(func() error {
-		for gctx.Err() == nil {
-			if err := readWithDeadline(c.videoConn, hdr); err != nil {
-				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-					continue
-				}
+	hs := Handshake{
+		Video:   c.videoConn != nil,
+		Audio:   c.audioConn != nil,
+		Control: c.controlConn != nil,
+	}
 
-				return fmt.Errorf("read video header: %w", err)
-			}
+	if c.opts.SendDeviceMeta {
+		nameRaw := make([]byte, deviceNameLen)
 
-			size := binary.BigEndian.Uint32(hdr[8:12])
-			if size == 0 {
-				continue
-			}
+		if err := readExactly(first, deviceNameLen, nameRaw); err != nil {
+			return handshakeErr(ctx, "name", err)
+		}
 
-			if _, err := io.CopyN(pw, c.videoConn, int64(size)); err != nil {
-				return fmt.Errorf("read video: %w", err)
-			}
+		if i := bytes.IndexByte(nameRaw, 0); i >= 0 {
+			nameRaw = nameRaw[:i]
 		}
 
-		return nil
-	})
+		hs.DeviceName = string(nameRaw)
+		hs.DeviceMeta = true
+	}
 
-	eg.Go(func() error {
-		<-gctx.Done()
+	if c.videoConn != nil && c.opts.SendCodecMeta {
+		meta := make([]byte, videoHeaderLen)
 
-		return errors.Join(pw.Close(), pr.Close())
-	})
+		if err := readExactly(c.videoConn, codecIDLen, meta[:codecIDLen]); err != nil {
+			return handshakeErr(ctx, "video meta", err)
+		}
 
-	return eg.Wait()
-}
+		hs.CodecID = Codec(binary.BigEndian.Uint32(meta[:codecIDLen]))
+		hs.VideoMeta = true
 
-func (c *Client) readControl(ctx context.Context) error {
-	for ctx.Err() == nil {
-		header := make([]byte, 1)
+		if err := checkCodec("video", hs.CodecID); err != nil {
+			return err
+		}
 
-		if err := readWithDeadline(c.controlConn, header); err != nil {
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				continue
+		if hs.CodecID != CodecDisabled {
+			if err := readExactly(c.videoConn, videoHeaderLen-codecIDLen, meta[codecIDLen:]); err != nil {
+				return handshakeErr(ctx, "video meta", err)
 			}
 
-			return fmt.Errorf("read control header: %w", err)
+			hs.Width = binary.BigEndian.Uint32(meta[4:8])
+			hs.Height = binary.BigEndian.Uint32(meta[8:12])
 		}
+	}
 
-		buf := make([]byte, 4096)
+	if c.audioConn != nil && c.opts.SendCodecMeta {
+		meta := make([]byte, audioHeaderLen)
 
-		n, err := c.controlConn.Read(buf)
-		if err != nil && !errors.Is(err, io.EOF) {
-			return fmt.Errorf("read control: %w", err)
+		if err := readExactly(c.audioConn, audioHeaderLen, meta); err != nil {
+			return handshakeErr(ctx, "audio meta", err)
 		}
 
-		if c.controlHandler != nil {
-			if err := c.controlHandler(ctx, ControlMessage{
-				Type:    DeviceMessageType(header[0]),
-				Payload: append([]byte(nil), buf[:n]...),
-			}); err != nil {
-				return fmt.Errorf("control handler: %w", err)
-			}
+		hs.AudioCodecID = Codec(binary.BigEndian.Uint32(meta))
+		hs.AudioMeta = true
+
+		if err := checkCodec("audio", hs.AudioCodecID); err != nil {
+			return err
 		}
 	}
 
+	c.handshake = hs
+
 	return nil
 }
 
-func readWithDeadline(conn net.Conn, b []byte) error {
-	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+func checkCodec(stream string, id Codec) error {
+	if id == CodecError {
+		return fmt.Errorf("%s: %w", stream, ErrStreamConfig)
+	}
 
-	return readExactly(conn, len(b), b)
+	return nil
+}
+
+func handshakeErr(ctx context.Context, part string, err error) error {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	return fmt.Errorf("handshake %s: %w", part, err)
+}
+
+func (c *Client) Close() error {
+	var errs []error
+
+	if c.writer != nil {
+		if err := c.releaseTouches(); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := c.destroyAllUhid(); err != nil {
+			errs = append(errs, err)
+		}
+
+		c.writer.close()
+	}
+
+	for _, s := range c.sockets() {
+		if *s.conn == nil {
+			continue
+		}
+
+		if err := (*s.conn).Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s close: %w", s.name, err))
+		}
+	}
+
+	for _, cl := range c.closers {
+		if err := cl.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) SetVideoHandler(h VideoHandler) { c.videoHandler = h }
+
+func (c *Client) SetAudioHandler(h AudioHandler) { c.audioHandler = h }
+
+func (c *Client) SetVideoPacketHandler(h PacketHandler) { c.videoPackets = h }
+
+func (c *Client) SetAudioPacketHandler(h PacketHandler) { c.audioPackets = h }
+
+func (c *Client) SetControlHandler(h ControlHandler) { c.controlHandler = h }
+
+func (c *Client) OnClipboard(fn func(text string)) { c.onClipboard = fn }
+
+func (c *Client) OnAckClipboard(fn func(seq uint64)) { c.onAckClipboard = fn }
+
+func (c *Client) OnUhidOutput(fn func(id uint16, data []byte)) { c.onUhidOutput = fn }
+
+func (c *Client) OnSend(fn func(msg protocol.ControlMessage)) func() {
+	h := &sendHook{fn: fn}
+
+	c.sendMutex.Lock()
+	c.onSend = append(c.onSend, h)
+	c.sendMutex.Unlock()
+
+	return func() {
+		c.sendMutex.Lock()
+		defer c.sendMutex.Unlock()
+
+		c.onSend = slices.DeleteFunc(slices.Clone(c.onSend), func(x *sendHook) bool { return x == h })
+	}
+}
+
+func (c *Client) GetHandshake() Handshake { return c.handshake }
+
+func (c *Client) Options() DialOptions { return c.opts }
+
+func (c *Client) Serve(ctx context.Context) error {
+	eg, gctx := errgroup.WithContext(ctx)
+	gctx, cancel := context.WithCancel(gctx)
+	defer cancel()
+
+	if c.videoConn != nil && !(c.handshake.VideoMeta && c.handshake.CodecID == CodecDisabled) {
+		eg.Go(func() error {
+			defer cancel()
+
+			return readStream(gctx, "video", c.videoConn, c.videoHandler, c.trackFrameSize(c.videoPackets))
+		})
+	}
+
+	if c.audioConn != nil && !(c.handshake.AudioMeta && c.handshake.AudioCodecID == CodecDisabled) {
+		eg.Go(func() error {
+			defer cancel()
+
+			return readStream(gctx, "audio", c.audioConn, c.audioHandler, c.audioPackets)
+		})
+	}
+
+	if c.controlConn != nil {
+		eg.Go(func() error {
+			defer cancel()
+
+			return c.readControl(gctx)
+		})
+	}
+
+	err := eg.Wait()
+
+	if c.writer != nil {
+		c.releaseActiveTouches()
+	}
+
+	return err
+}
+
+func (c *Client) readControl(ctx context.Context) error {
+	_ = c.controlConn.SetReadDeadline(time.Time{})
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.controlConn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	for {
+		msg, err := readDeviceMessage(c.controlConn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("read control: %w", err)
+		}
+
+		c.dispatchDevice(msg)
+
+		if c.controlHandler != nil {
+			if err := c.controlHandler(ctx, msg); err != nil {
+				return fmt.Errorf("control handler: %w", err)
+			}
+		}
+	}
 }
 
 func readExactly(r io.Reader, n int, buf []byte) error {
@@ -0,0 +1,212 @@
+package scrcpy_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return ctx
+}
+
+func dialTest(t *testing.T, cfg scrcpytest.Config, opts ...scrcpy.DialOption) (*scrcpytest.Server, *scrcpy.Client) {
+	t.Helper()
+
+	ctx := testContext(t)
+
+	srv, err := scrcpytest.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = srv.Close() })
+
+	c, err := srv.Dial(ctx, opts...)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := srv.WaitConnected(ctx); err != nil {
+		t.Fatalf("WaitConnected: %v", err)
+	}
+
+	return srv, c
+}
+
+func serveTest(t *testing.T, c *scrcpy.Client) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- c.Serve(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+}
+
+func TestDial(t *testing.T) {
+	for mask := 1; mask < 1<<6; mask++ {
+		cfg := scrcpytest.DefaultConfig()
+		cfg.AudioCodec = scrcpy.CodecAAC
+		cfg.Video = mask&1 != 0
+		cfg.Audio = mask&2 != 0
+		cfg.Control = mask&4 != 0
+
+		if !cfg.Video && !cfg.Audio && !cfg.Control {
+			continue
+		}
+
+		for flags := range 1 << 3 {
+			cfg.SendDummyByte = flags&1 != 0
+			cfg.SendDeviceMeta = flags&2 != 0
+			cfg.SendCodecMeta = flags&4 != 0
+
+			name := fmt.Sprintf("video=%t/audio=%t/control=%t/dummy=%t/meta=%t/codec=%t",
+				cfg.Video, cfg.Audio, cfg.Control, cfg.SendDummyByte, cfg.SendDeviceMeta, cfg.SendCodecMeta)
+
+			t.Run(name, func(t *testing.T) {
+				_, c := dialTest(t, cfg)
+				hs := c.GetHandshake()
+
+				want := scrcpy.Handshake{
+					Video:      cfg.Video,
+					Audio:      cfg.Audio,
+					Control:    cfg.Control,
+					DeviceMeta: cfg.SendDeviceMeta,
+					VideoMeta:  cfg.Video && cfg.SendCodecMeta,
+					AudioMeta:  cfg.Audio && cfg.SendCodecMeta,
+				}
+
+				if cfg.SendDeviceMeta {
+					want.DeviceName = cfg.DeviceName
+				}
+
+				if want.VideoMeta {
+					want.CodecID, want.Width, want.Height = cfg.VideoCodec, cfg.Width, cfg.Height
+				}
+
+				if want.AudioMeta {
+					want.AudioCodecID = cfg.AudioCodec
+				}
+
+				if hs != want {
+					t.Fatalf("handshake = %+v, want %+v", hs, want)
+				}
+
+				if w, h := c.FrameSize(); w != want.Width || h != want.Height {
+					t.Fatalf("FrameSize = %dx%d, want %dx%d", w, h, want.Width, want.Height)
+				}
+			})
+		}
+	}
+}
+
+func TestDialNoSockets(t *testing.T) {
+	_, err := scrcpy.Dial(testContext(t), "127.0.0.1:0",
+		scrcpy.WithVideo(false), scrcpy.WithAudio(false), scrcpy.WithControl(false))
+	if !errors.Is(err, scrcpy.ErrNoSockets) {
+		t.Fatalf("Dial = %v, want ErrNoSockets", err)
+	}
+}
+
+func TestDialStreamConfigError(t *testing.T) {
+	cfg := scrcpytest.DefaultConfig()
+	cfg.VideoCodec = scrcpy.CodecError
+
+	srv, err := scrcpytest.NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer srv.Close()
+
+	if _, err := srv.Dial(testContext(t)); !errors.Is(err, scrcpy.ErrStreamConfig) {
+		t.Fatalf("Dial = %v, want ErrStreamConfig", err)
+	}
+}
+
+func TestControlDisabled(t *testing.T) {
+	cfg := scrcpytest.DefaultConfig()
+	cfg.Control = false
+	_, c := dialTest(t, cfg)
+
+	if err := c.InjectText("x"); !errors.Is(err, scrcpy.ErrControlDisabled) {
+		t.Fatalf("InjectText = %v, want ErrControlDisabled", err)
+	}
+}
+
+func TestDialWaitsForDummyByte(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	type result struct {
+		c   *scrcpy.Client
+		err error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		c, err := scrcpy.Dial(testContext(t), ln.Addr().String(),
+			scrcpy.WithAudio(false), scrcpy.WithDeviceMeta(false), scrcpy.WithCodecMeta(false))
+		done <- result{c, err}
+	}()
+
+	video, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer video.Close()
+
+	_ = ln.(*net.TCPListener).SetDeadline(time.Now().Add(100 * time.Millisecond))
+
+	if conn, err := ln.Accept(); err == nil {
+		conn.Close()
+		t.Fatal("control socket dialed before the dummy byte was read")
+	}
+
+	_ = ln.(*net.TCPListener).SetDeadline(time.Time{})
+
+	if _, err := video.Write([]byte{0}); err != nil {
+		t.Fatal(err)
+	}
+
+	control, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer control.Close()
+
+	res := <-done
+	if res.err != nil {
+		t.Fatalf("Dial: %v", res.err)
+	}
+
+	_ = res.c.Close()
+}
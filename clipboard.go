@@ -0,0 +1,116 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+const (
+	CopyKeyNone = protocol.CopyKeyNone
+	CopyKeyCopy = protocol.CopyKeyCopy
+	CopyKeyCut  = protocol.CopyKeyCut
+)
+
+type clipWaiter struct {
+	ch    chan string
+	armed bool
+}
+
+func (c *Client) GetClipboardText(ctx context.Context, copyKey byte) (string, error) {
+	w := &clipWaiter{ch: make(chan string, 1)}
+
+	c.waitMutex.Lock()
+	c.clipWaiters = append(c.clipWaiters, w)
+	c.waitMutex.Unlock()
+
+	defer c.removeClipWaiter(w)
+
+	arm := func() {
+		c.waitMutex.Lock()
+		w.armed = true
+		c.waitMutex.Unlock()
+	}
+
+	if err := c.send(ctx, &protocol.GetClipboard{CopyKey: copyKey}, arm); err != nil {
+		return "", err
+	}
+
+	select {
+	case text := <-w.ch:
+		return text, nil
+	case <-c.writer.stopped:
+		return "", ErrClosed
+	case <-ctx.Done():
+		return "", fmt.Errorf("get clipboard: %w", ctx.Err())
+	}
+}
+
+func (c *Client) SetClipboardAndWait(ctx context.Context, text string, paste bool) error {
+	seq := c.clipSeq.Add(1)
+	ch := make(chan struct{})
+
+	c.waitMutex.Lock()
+	if c.ackWaiters == nil {
+		c.ackWaiters = make(map[uint64]chan struct{})
+	}
+	c.ackWaiters[seq] = ch
+	c.waitMutex.Unlock()
+
+	defer func() {
+		c.waitMutex.Lock()
+		delete(c.ackWaiters, seq)
+		c.waitMutex.Unlock()
+	}()
+
+	if err := c.SetClipboardCtx(ctx, seq, text, paste); err != nil {
+		return err
+	}
+
+	select {
+	case <-ch:
+		return nil
+	case <-c.writer.stopped:
+		return ErrClosed
+	case <-ctx.Done():
+		return fmt.Errorf("set clipboard: %w", ctx.Err())
+	}
+}
+
+func (c *Client) removeClipWaiter(w *clipWaiter) {
+	c.waitMutex.Lock()
+	defer c.waitMutex.Unlock()
+
+	c.clipWaiters = slices.DeleteFunc(c.clipWaiters, func(cw *clipWaiter) bool { return cw == w })
+}
+
+func (c *Client) notifyClipboard(text string) {
+	var ready []*clipWaiter
+
+	c.waitMutex.Lock()
+	c.clipWaiters = slices.DeleteFunc(c.clipWaiters, func(w *clipWaiter) bool {
+		if w.armed {
+			ready = append(ready, w)
+		}
+
+		return w.armed
+	})
+	c.waitMutex.Unlock()
+
+	for _, w := range ready {
+		w.ch <- text
+	}
+}
+
+func (c *Client) notifyAckClipboard(seq uint64) {
+	c.waitMutex.Lock()
+	ch, ok := c.ackWaiters[seq]
+	delete(c.ackWaiters, seq)
+	c.waitMutex.Unlock()
+
+	if ok {
+		close(ch)
+	}
+}
@@ -0,0 +1,94 @@
+package scrcpy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func TestGetClipboardText(t *testing.T) {
+	cfg := scrcpytest.DefaultConfig()
+	cfg.Clipboard = "device text"
+	srv, c := dialTest(t, cfg)
+
+	synced := make(chan string, 1)
+	c.OnClipboard(func(text string) {
+		if text == "autosync" {
+			synced <- text
+		}
+	})
+
+	serveTest(t, c)
+
+	ctx := testContext(t)
+
+	if err := srv.SendDeviceMessage(&protocol.Clipboard{Text: "autosync"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-synced:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for autosync clipboard")
+	}
+
+	text, err := c.GetClipboardText(ctx, protocol.CopyKeyCopy)
+	if err != nil {
+		t.Fatalf("GetClipboardText: %v", err)
+	}
+
+	if text != "device text" {
+		t.Fatalf("GetClipboardText = %q, want %q", text, "device text")
+	}
+
+	msgs, err := srv.WaitMessages(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m, ok := msgs[0].(*protocol.GetClipboard); !ok || m.CopyKey != protocol.CopyKeyCopy {
+		t.Fatalf("message = %v, want GetClipboard(copy)", msgs[0])
+	}
+}
+
+func TestGetClipboardTextCancelled(t *testing.T) {
+	_, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.GetClipboardText(ctx, protocol.CopyKeyNone); err == nil {
+		t.Fatal("GetClipboardText with cancelled context succeeded")
+	}
+}
+
+func TestSetClipboardAndWait(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	serveTest(t, c)
+
+	ctx := testContext(t)
+
+	for i, text := range []string{"first", "second"} {
+		if err := c.SetClipboardAndWait(ctx, text, i == 1); err != nil {
+			t.Fatalf("SetClipboardAndWait(%q): %v", text, err)
+		}
+
+		if got := srv.Clipboard(); got != text {
+			t.Fatalf("server clipboard = %q, want %q", got, text)
+		}
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+
+	first, _ := msgs[0].(*protocol.SetClipboard)
+	second, _ := msgs[1].(*protocol.SetClipboard)
+
+	if first == nil || second == nil || first.Paste || !second.Paste || first.Sequence == 0 || second.Sequence <= first.Sequence {
+		t.Fatalf("messages = %v", msgs)
+	}
+}
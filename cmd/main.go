@@ -18,10 +18,16 @@ func main() {
 		return
 	}
 
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Printf("close: %v", err)
+		}
+	}()
+
 	device := client.GetHandshake()
-	log.Printf("Connected to %s (%dx%d, codec=%d)\n", device.DeviceName, device.Width, device.Height, device.CodecID)
+	log.Printf("Connected to %s (%dx%d, codec=%s)\n", device.DeviceName, device.Width, device.Height, device.CodecID)
 
-	dec, err := scrcpy.NewDecoder(ctx)
+	dec, err := scrcpy.NewDecoder(ctx, device.CodecID)
 	if err != nil {
 		log.Printf("decoder: %v", err)
 
@@ -34,7 +40,10 @@ func main() {
 		}
 	}()
 
+	frames := newFrameSizes(device.CodecID, device.Width, device.Height)
+
 	client.SetVideoHandler(dec.VideoHandler)
+	client.SetVideoPacketHandler(frames.packet)
 
 	go func() {
 		err := client.Serve(ctx)
@@ -43,7 +52,7 @@ func main() {
 		}
 	}()
 
-	if err := AppUI(ctx, client, dec); err != nil {
+	if err := AppUI(ctx, client, dec, frames); err != nil {
 		log.Printf("ui: %v", err)
 	}
 }
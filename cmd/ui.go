@@ -2,24 +2,86 @@ package main
 
 import (
 	"context"
+	"errors"
 	"image"
 	"io"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
 	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/keymap"
 	"github.com/qeesung/image2ascii/convert"
 )
 
+const cellAspect = 0.5
+
 type StateUI struct {
-	mutex  sync.Mutex
-	client *scrcpy.Client
-	screen tcell.Screen
+	viewMutex sync.Mutex
+	client    *scrcpy.Client
+	touch     *scrcpy.TouchSession
+	capture   *scrcpy.MouseCapture
+	keys      *keymap.Translator
+	screen    tcell.Screen
+	view      *scrcpy.Viewport
+	width     int
+	height    int
+}
+
+type videoFrame struct {
+	data   []byte
 	width  int
 	height int
 }
 
-func AppUI(ctx context.Context, client *scrcpy.Client, decoder *scrcpy.FFmpeg) error {
+type frameSizes struct {
+	mutex   sync.Mutex
+	codec   scrcpy.Codec
+	frames  uint64
+	width   uint32
+	height  uint32
+	changes []frameSizeChange
+}
+
+type frameSizeChange struct {
+	frame  uint64
+	width  uint32
+	height uint32
+}
+
+func newFrameSizes(codec scrcpy.Codec, width, height uint32) *frameSizes {
+	return &frameSizes{codec: codec, width: width, height: height}
+}
+
+func (t *frameSizes) packet(_ context.Context, pkt scrcpy.Packet) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !pkt.IsConfig {
+		t.frames++
+
+		return nil
+	}
+
+	if w, h, err := scrcpy.ParseFrameSize(t.codec, pkt.Data); err == nil {
+		t.changes = append(t.changes, frameSizeChange{frame: t.frames, width: w, height: h})
+	}
+
+	return nil
+}
+
+func (t *frameSizes) size(frame uint64) (uint32, uint32) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for len(t.changes) > 0 && t.changes[0].frame <= frame {
+		t.width, t.height = t.changes[0].width, t.changes[0].height
+		t.changes = t.changes[1:]
+	}
+
+	return t.width, t.height
+}
+
+func AppUI(ctx context.Context, client *scrcpy.Client, decoder *scrcpy.FFmpeg, frames *frameSizes) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -36,24 +98,67 @@ func AppUI(ctx context.Context, client *scrcpy.Client, decoder *scrcpy.FFmpeg) e
 
 	screen.EnableMouse()
 
-	hs := client.GetHandshake()
+	width, height := frames.size(0)
+
+	touch := client.NewTouchSessionCtx(ctx)
+	defer touch.Close()
+
+	cols, rows := screen.Size()
+	view := scrcpy.NewViewport(float64(cols), float64(rows), float64(width), float64(height))
+	view.PixelAspect = cellAspect
 
 	state := StateUI{
 		client: client,
+		touch:  touch,
+		keys:   keymap.New(keymap.US),
 		screen: screen,
-		width:  int(hs.Width),
-		height: int(hs.Height),
+		view:   view,
+		width:  int(width),
+		height: int(height),
 	}
 
+	rendered := make(chan videoFrame, 1)
+
 	go func() {
-		buf := make([]byte, state.width*state.height*3)
+		defer close(rendered)
+
+		head := make([]byte, 1)
 
-		for ctx.Err() == nil {
-			if _, err := io.ReadFull(decoder, buf); err != nil {
+		var buf []byte
+
+		for n := uint64(0); ctx.Err() == nil; n++ {
+			if _, err := io.ReadFull(decoder, head); err != nil {
+				return
+			}
+
+			w, h := frames.size(n)
+
+			size := int(w) * int(h) * 3
+			if size == 0 {
+				return
+			}
+
+			if len(buf) != size {
+				buf = make([]byte, size)
+			}
+
+			buf[0] = head[0]
+
+			if _, err := io.ReadFull(decoder, buf[1:]); err != nil {
 				return
 			}
 
-			go state.img2tcell(buf)
+			select {
+			case rendered <- videoFrame{data: buf, width: int(w), height: int(h)}:
+				buf = nil
+			default:
+			}
+		}
+	}()
+
+	go func() {
+		for f := range rendered {
+			state.img2tcell(f)
 		}
 	}()
 
@@ -63,56 +168,220 @@ func AppUI(ctx context.Context, client *scrcpy.Client, decoder *scrcpy.FFmpeg) e
 }
 
 func (s *StateUI) eventsHandler(ctx context.Context) {
-	var primaryKeyPressed bool
+	pointerID := s.touch.Alloc()
+
+	defer func() {
+		if s.capture != nil {
+			_ = s.capture.Mouse().Close()
+		}
+	}()
 
 	for ctx.Err() == nil {
 		ev := s.screen.PollEvent()
 		switch ev := ev.(type) {
 		case *tcell.EventResize:
+			cols, rows := ev.Size()
+
+			s.viewMutex.Lock()
+			s.view.SetSurfaceSize(float64(cols), float64(rows))
+			s.viewMutex.Unlock()
+
 			s.screen.Clear()
 		case *tcell.EventKey:
-			if ev.Key() == tcell.KeyEscape || ev.Rune() == 'q' {
+			if ev.Key() == tcell.KeyCtrlC || ev.Key() == tcell.KeyCtrlQ {
 				return
 			}
+
+			if ev.Key() == tcell.KeyCtrlG {
+				s.toggleCapture(ctx)
+
+				continue
+			}
+
+			if kev, ok := keyEvent(ev); ok {
+				if err := s.keys.Press(ctx, s.client, kev); errors.Is(err, keymap.ErrUnmapped) && kev.Key == keymap.KeyRune {
+					_ = s.client.InjectTextCtx(ctx, string(kev.Rune))
+				}
+			}
 		case *tcell.EventMouse:
-			w, h := s.screen.Size()
+			if s.capture != nil && s.capture.Captured() {
+				s.captureMouse(ctx, ev)
+
+				continue
+			}
+
 			x, y := ev.Position()
 
-			rx := uint32(float32(s.width) / float32(w) * float32(x))
-			ry := uint32(float32(s.height) / float32(h) * float32(y))
+			s.viewMutex.Lock()
+			rx, ry, inside := s.view.ToFrame(float64(x)+0.5, float64(y)+0.5)
+			s.viewMutex.Unlock()
+
+			if !inside {
+				if ev.Buttons() != tcell.Button1 {
+					_ = s.touch.Release(ctx)
+				}
+
+				continue
+			}
 
 			if ev.Buttons() == tcell.Button1 {
-				if !primaryKeyPressed {
-					primaryKeyPressed = true
-					_ = s.client.InjectTouch(scrcpy.ActionDown, 1, rx, ry, 65535, scrcpy.ButtonPrimary, scrcpy.ButtonPrimary)
+				if !s.touch.IsActive(pointerID) {
+					_ = s.touch.InjectTouchCtx(ctx, scrcpy.ActionDown, pointerID, rx, ry, 65535, scrcpy.ButtonPrimary, scrcpy.ButtonPrimary)
 				} else {
-					_ = s.client.InjectTouch(scrcpy.ActionMove, 1, rx, ry, 65535, 0, scrcpy.ButtonPrimary)
+					_ = s.touch.InjectTouchCtx(ctx, scrcpy.ActionMove, pointerID, rx, ry, 65535, 0, scrcpy.ButtonPrimary)
 				}
 
 				continue
 			}
 
-			if primaryKeyPressed {
-				primaryKeyPressed = false
-				_ = s.client.InjectTouch(scrcpy.ActionUp, 1, rx, ry, 65535, scrcpy.ButtonPrimary, 0)
+			if s.touch.IsActive(pointerID) {
+				_ = s.touch.InjectTouchCtx(ctx, scrcpy.ActionUp, pointerID, rx, ry, 65535, scrcpy.ButtonPrimary, 0)
 			}
 		}
 	}
 }
 
-func (s *StateUI) img2tcell(buf []byte) {
-	if !s.mutex.TryLock() {
+func (s *StateUI) syncFrameSize(width, height int) {
+	if width == s.width && height == s.height {
 		return
 	}
 
-	defer s.mutex.Unlock()
+	s.width, s.height = width, height
+
+	s.viewMutex.Lock()
+	s.view.SetFrameSize(float64(width), float64(height))
+	s.viewMutex.Unlock()
+
+	s.screen.Clear()
+}
+
+func (s *StateUI) toggleCapture(ctx context.Context) {
+	if s.capture == nil {
+		mouse, err := s.client.NewHIDMouse(ctx, scrcpy.HIDMouseID)
+		if err != nil {
+			return
+		}
+
+		s.capture = scrcpy.NewMouseCapture(mouse)
+	}
+
+	_ = s.touch.Release(ctx)
+	_, _ = s.capture.Toggle(ctx)
+}
+
+func (s *StateUI) captureMouse(ctx context.Context, ev *tcell.EventMouse) {
+	s.viewMutex.Lock()
+	_, _, w, h := s.view.DisplayRect()
+	fw, fh := s.view.FrameWidth, s.view.FrameHeight
+	s.viewMutex.Unlock()
+
+	if w > 0 && h > 0 {
+		s.capture.SetScale(fw/w, fh/h)
+	}
+
+	x, y := ev.Position()
+	_ = s.capture.Motion(ctx, float64(x), float64(y))
+
+	var buttons uint8
+
+	if ev.Buttons()&tcell.Button1 != 0 {
+		buttons |= scrcpy.HIDMouseLeft
+	}
+
+	if ev.Buttons()&tcell.Button2 != 0 {
+		buttons |= scrcpy.HIDMouseRight
+	}
+
+	if ev.Buttons()&tcell.Button3 != 0 {
+		buttons |= scrcpy.HIDMouseMiddle
+	}
+
+	_ = s.capture.Buttons(ctx, buttons)
+
+	switch {
+	case ev.Buttons()&tcell.WheelUp != 0:
+		_ = s.capture.Wheel(ctx, 1, 0)
+	case ev.Buttons()&tcell.WheelDown != 0:
+		_ = s.capture.Wheel(ctx, -1, 0)
+	case ev.Buttons()&tcell.WheelLeft != 0:
+		_ = s.capture.Wheel(ctx, 0, -1)
+	case ev.Buttons()&tcell.WheelRight != 0:
+		_ = s.capture.Wheel(ctx, 0, 1)
+	}
+}
 
-	img := image.NewNRGBA(image.Rect(0, 0, s.width, s.height))
+var namedKeys = map[tcell.Key]keymap.Key{
+	tcell.KeyEnter:      keymap.KeyEnter,
+	tcell.KeyBackspace:  keymap.KeyBackspace,
+	tcell.KeyBackspace2: keymap.KeyBackspace,
+	tcell.KeyDelete:     keymap.KeyDelete,
+	tcell.KeyTab:        keymap.KeyTab,
+	tcell.KeyEscape:     keymap.KeyEscape,
+	tcell.KeyUp:         keymap.KeyUp,
+	tcell.KeyDown:       keymap.KeyDown,
+	tcell.KeyLeft:       keymap.KeyLeft,
+	tcell.KeyRight:      keymap.KeyRight,
+	tcell.KeyHome:       keymap.KeyHome,
+	tcell.KeyEnd:        keymap.KeyEnd,
+	tcell.KeyPgUp:       keymap.KeyPageUp,
+	tcell.KeyPgDn:       keymap.KeyPageDown,
+	tcell.KeyInsert:     keymap.KeyInsert,
+	tcell.KeyF1:         keymap.KeyF1,
+	tcell.KeyF2:         keymap.KeyF2,
+	tcell.KeyF3:         keymap.KeyF3,
+	tcell.KeyF4:         keymap.KeyF4,
+	tcell.KeyF5:         keymap.KeyF5,
+	tcell.KeyF6:         keymap.KeyF6,
+	tcell.KeyF7:         keymap.KeyF7,
+	tcell.KeyF8:         keymap.KeyF8,
+	tcell.KeyF9:         keymap.KeyF9,
+	tcell.KeyF10:        keymap.KeyF10,
+	tcell.KeyF11:        keymap.KeyF11,
+	tcell.KeyF12:        keymap.KeyF12,
+}
+
+func keyEvent(ev *tcell.EventKey) (keymap.Event, bool) {
+	var mods keymap.Modifier
+
+	if ev.Modifiers()&tcell.ModShift != 0 {
+		mods |= keymap.ModShift
+	}
 
-	for i := 0; i < s.width*s.height; i++ {
-		b := buf[3*i+0]
-		g := buf[3*i+1]
-		r := buf[3*i+2]
+	if ev.Modifiers()&tcell.ModCtrl != 0 {
+		mods |= keymap.ModCtrl
+	}
+
+	if ev.Modifiers()&tcell.ModAlt != 0 {
+		mods |= keymap.ModAlt
+	}
+
+	if ev.Modifiers()&tcell.ModMeta != 0 {
+		mods |= keymap.ModMeta
+	}
+
+	if key, ok := namedKeys[ev.Key()]; ok {
+		return keymap.Event{Key: key, Mods: mods}, true
+	}
+
+	switch {
+	case ev.Key() == tcell.KeyRune:
+		return keymap.Event{Key: keymap.KeyRune, Rune: ev.Rune(), Mods: mods &^ keymap.ModShift}, true
+	case ev.Key() >= tcell.KeyCtrlA && ev.Key() <= tcell.KeyCtrlZ:
+		return keymap.Event{Key: keymap.KeyRune, Rune: rune('a' + ev.Key() - tcell.KeyCtrlA), Mods: mods | keymap.ModCtrl}, true
+	}
+
+	return keymap.Event{}, false
+}
+
+func (s *StateUI) img2tcell(f videoFrame) {
+	s.syncFrameSize(f.width, f.height)
+
+	img := image.NewNRGBA(image.Rect(0, 0, f.width, f.height))
+
+	for i := 0; i < f.width*f.height; i++ {
+		b := f.data[3*i+0]
+		g := f.data[3*i+1]
+		r := f.data[3*i+2]
 
 		j := 4 * i
 		img.Pix[j+0] = r
@@ -121,14 +390,22 @@ func (s *StateUI) img2tcell(buf []byte) {
 		img.Pix[j+3] = 0xff
 	}
 
+	s.viewMutex.Lock()
+	left, top, w, h := s.view.DisplayRect()
+	s.viewMutex.Unlock()
+
+	opts := convert.DefaultOptions
+	opts.FixedWidth = max(int(w), 1)
+	opts.FixedHeight = max(int(h), 1)
+
 	converter := convert.NewImageConverter()
-	charMatrix := converter.Image2CharPixelMatrix(img, &convert.DefaultOptions)
+	charMatrix := converter.Image2CharPixelMatrix(img, &opts)
 
 	for y, row := range charMatrix {
 		for x, char := range row {
 			color := tcell.NewRGBColor(int32(char.R), int32(char.G), int32(char.B))
 
-			s.screen.SetContent(x, y, rune(char.Char), nil, tcell.StyleDefault.Foreground(color))
+			s.screen.SetContent(int(left)+x, int(top)+y, rune(char.Char), nil, tcell.StyleDefault.Foreground(color))
 		}
 	}
 
@@ -0,0 +1,60 @@
+package scrcpy
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Codec uint32
+
+const (
+	CodecDisabled Codec = 0
+	CodecError    Codec = 1
+	CodecH264     Codec = 0x68_32_36_34
+	CodecH265     Codec = 0x68_32_36_35
+	CodecAV1      Codec = 0x00_61_76_31
+	CodecOpus     Codec = 0x6f_70_75_73
+	CodecAAC      Codec = 0x00_61_61_63
+	CodecFLAC     Codec = 0x66_6c_61_63
+	CodecRaw      Codec = 0x00_72_61_77
+)
+
+var codecNames = map[Codec]string{
+	CodecDisabled: "disabled",
+	CodecError:    "error",
+	CodecH264:     "h264",
+	CodecH265:     "h265",
+	CodecAV1:      "av1",
+	CodecOpus:     "opus",
+	CodecAAC:      "aac",
+	CodecFLAC:     "flac",
+	CodecRaw:      "raw",
+}
+
+func ParseCodec(s string) (Codec, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	for codec, n := range codecNames {
+		if n == name && codec != CodecDisabled && codec != CodecError {
+			return codec, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnsupportedCodec, s)
+}
+
+func (c Codec) String() string {
+	if name, ok := codecNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("codec(0x%08x)", uint32(c))
+}
+
+func (c Codec) IsVideo() bool {
+	return c == CodecH264 || c == CodecH265 || c == CodecAV1
+}
+
+func (c Codec) IsAudio() bool {
+	return c == CodecOpus || c == CodecAAC || c == CodecFLAC || c == CodecRaw
+}
@@ -1,38 +1,40 @@
 package scrcpy
 
-type ControlMessageType byte
-type DeviceMessageType byte
+import "github.com/merzzzl/scrcpy-go/protocol"
+
+type ControlMessageType = protocol.ControlMessageType
+type DeviceMessageType = protocol.DeviceMessageType
 
 const (
-	CtrlInjectKeycode            ControlMessageType = 0
-	CtrlInjectText               ControlMessageType = 1
-	CtrlInjectTouchEvent         ControlMessageType = 2
-	CtrlInjectScrollEvent        ControlMessageType = 3
-	CtrlBackOrScreenOn           ControlMessageType = 4
-	CtrlExpandNotificationPanel  ControlMessageType = 5
-	CtrlExpandSettingsPanel      ControlMessageType = 6
-	CtrlCollapsePanels           ControlMessageType = 7
-	CtrlGetClipboard             ControlMessageType = 8
-	CtrlSetClipboard             ControlMessageType = 9
-	CtrlSetDisplayPower          ControlMessageType = 10
-	CtrlRotateDevice             ControlMessageType = 11
-	CtrlUhidCreate               ControlMessageType = 12
-	CtrlUhidInput                ControlMessageType = 13
-	CtrlUhidDestroy              ControlMessageType = 14
-	CtrlOpenHardKeyboardSettings ControlMessageType = 15
-	CtrlStartApp                 ControlMessageType = 16
+	CtrlInjectKeycode            = protocol.CtrlInjectKeycode
+	CtrlInjectText               = protocol.CtrlInjectText
+	CtrlInjectTouchEvent         = protocol.CtrlInjectTouchEvent
+	CtrlInjectScrollEvent        = protocol.CtrlInjectScrollEvent
+	CtrlBackOrScreenOn           = protocol.CtrlBackOrScreenOn
+	CtrlExpandNotificationPanel  = protocol.CtrlExpandNotificationPanel
+	CtrlExpandSettingsPanel      = protocol.CtrlExpandSettingsPanel
+	CtrlCollapsePanels           = protocol.CtrlCollapsePanels
+	CtrlGetClipboard             = protocol.CtrlGetClipboard
+	CtrlSetClipboard             = protocol.CtrlSetClipboard
+	CtrlSetDisplayPower          = protocol.CtrlSetDisplayPower
+	CtrlRotateDevice             = protocol.CtrlRotateDevice
+	CtrlUhidCreate               = protocol.CtrlUhidCreate
+	CtrlUhidInput                = protocol.CtrlUhidInput
+	CtrlUhidDestroy              = protocol.CtrlUhidDestroy
+	CtrlOpenHardKeyboardSettings = protocol.CtrlOpenHardKeyboardSettings
+	CtrlStartApp                 = protocol.CtrlStartApp
 )
 
 const (
-	DeviceClipboard    DeviceMessageType = 0
-	DeviceAckClipboard DeviceMessageType = 1
-	DeviceUhidOutput   DeviceMessageType = 2
+	DeviceClipboard    = protocol.DeviceClipboard
+	DeviceAckClipboard = protocol.DeviceAckClipboard
+	DeviceUhidOutput   = protocol.DeviceUhidOutput
 )
 
 const (
-	ActionDown byte = 0
-	ActionUp   byte = 1
-	ActionMove byte = 2
+	ActionDown = protocol.ActionDown
+	ActionUp   = protocol.ActionUp
+	ActionMove = protocol.ActionMove
 )
 
 const (
@@ -40,21 +42,24 @@ const (
 	PowerModeOn  = 1
 )
 
-const (
-	lenInjectKeycode = 1 + 1 + 4 + 4 + 4
-	lenInjectTouch   = 1 + 1 + 8 + 4 + 4 + 2 + 2 + 2 + 4 + 4
-	lenInjectScroll  = 1 + 4 + 4 + 2 + 2 + 2 + 2 + 4
-	maxTextLength    = 300
-	maxClipLength    = (1<<18 - 14)
-)
-
 const (
 	dummyLen       = 1
 	deviceNameLen  = 64
+	codecIDLen     = 4
 	videoHeaderLen = 12
+	audioHeaderLen = 4
 	frameHeaderLen = 12
+	maxPacketLen   = 64 << 20
 )
 
+const (
+	packetFlagConfig   = uint64(1) << 63
+	packetFlagKeyFrame = uint64(1) << 62
+	packetPTSMask      = packetFlagKeyFrame - 1
+)
+
+const socketNamePrefix = "scrcpy"
+
 const (
 	ButtonNone            = 0
 	ButtonPrimary         = 1 << 0
@@ -66,6 +71,27 @@ const (
 	ButtonStylusSecondary = 1 << 6
 )
 
+const (
+	AndroidMetaNone         = 0
+	AndroidMetaShiftOn      = 0x01
+	AndroidMetaAltOn        = 0x02
+	AndroidMetaSymOn        = 0x04
+	AndroidMetaFunctionOn   = 0x08
+	AndroidMetaAltLeftOn    = 0x10
+	AndroidMetaAltRightOn   = 0x20
+	AndroidMetaShiftLeftOn  = 0x40
+	AndroidMetaShiftRightOn = 0x80
+	AndroidMetaCtrlOn       = 0x1000
+	AndroidMetaCtrlLeftOn   = 0x2000
+	AndroidMetaCtrlRightOn  = 0x4000
+	AndroidMetaMetaOn       = 0x10000
+	AndroidMetaMetaLeftOn   = 0x20000
+	AndroidMetaMetaRightOn  = 0x40000
+	AndroidMetaCapsLockOn   = 0x100000
+	AndroidMetaNumLockOn    = 0x200000
+	AndroidMetaScrollLockOn = 0x400000
+)
+
 const (
 	AndroidKeyUnknown                   = 0
 	AndroidKeySoftLeft                  = 1
@@ -191,6 +217,7 @@ const (
 	AndroidKeyBreak                     = 121
 	AndroidKeyMoveHome                  = 122
 	AndroidKeyMoveEnd                   = 123
+	AndroidKeyInsert                    = 124
 	AndroidKeyForward                   = 125
 	AndroidKeyMediaPlay                 = 126
 	AndroidKeyMediaPause                = 127
@@ -1,204 +1,209 @@
 package scrcpy
 
 import (
-	"bytes"
-	"encoding/binary"
-	"unicode/utf8"
+	"context"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
 )
 
 func (c *Client) InjectKeycode(keycode uint32, action byte, repeat, meta uint32) error {
-	buf := make([]byte, lenInjectKeycode)
-	buf[0] = byte(CtrlInjectKeycode)
-	buf[1] = action
-	binary.BigEndian.PutUint32(buf[2:], keycode)
-	binary.BigEndian.PutUint32(buf[6:], repeat)
-	binary.BigEndian.PutUint32(buf[10:], meta)
-	_, err := c.controlConn.Write(buf)
+	return c.InjectKeycodeCtx(context.Background(), keycode, action, repeat, meta)
+}
 
-	return err
+func (c *Client) InjectKeycodeCtx(ctx context.Context, keycode uint32, action byte, repeat, meta uint32) error {
+	return c.Send(ctx, &protocol.InjectKeycode{
+		Action:    action,
+		Keycode:   keycode,
+		Repeat:    repeat,
+		MetaState: meta,
+	})
 }
 
 func (c *Client) InjectText(text string) error {
-	if !utf8.ValidString(text) || len(text) > maxTextLength {
-		return ErrTextTooLong
-	}
-
-	var buf bytes.Buffer
-
-	_ = buf.WriteByte(byte(CtrlInjectText))
-	_ = binary.Write(&buf, binary.BigEndian, uint32(len(text)))
-	_, _ = buf.WriteString(text)
-	_, err := c.controlConn.Write(buf.Bytes())
+	return c.InjectTextCtx(context.Background(), text)
+}
 
-	return err
+func (c *Client) InjectTextCtx(ctx context.Context, text string) error {
+	return c.Send(ctx, &protocol.InjectText{Text: text})
 }
 
 func (c *Client) InjectTouch(action byte, pointerID uint64, x, y uint32, pressure uint16, actionButton, buttons uint32) error {
-	buf := make([]byte, lenInjectTouch)
-	buf[0] = byte(CtrlInjectTouchEvent)
-	buf[1] = action
-	binary.BigEndian.PutUint64(buf[2:], pointerID)
-	binary.BigEndian.PutUint32(buf[10:], x)
-	binary.BigEndian.PutUint32(buf[14:], y)
-	binary.BigEndian.PutUint16(buf[18:], uint16(c.handshake.Width))
-	binary.BigEndian.PutUint16(buf[20:], uint16(c.handshake.Height))
-	binary.BigEndian.PutUint16(buf[22:], pressure)
-	binary.BigEndian.PutUint32(buf[24:], actionButton)
-	binary.BigEndian.PutUint32(buf[28:], buttons)
-	_, err := c.controlConn.Write(buf)
-
-	return err
+	return c.InjectTouchCtx(context.Background(), action, pointerID, x, y, pressure, actionButton, buttons)
+}
+
+func (c *Client) InjectTouchCtx(ctx context.Context, action byte, pointerID uint64, x, y uint32, pressure uint16, actionButton, buttons uint32) error {
+	width, height := c.FrameSize()
+
+	return c.Send(ctx, &protocol.InjectTouchEvent{
+		Action:       action,
+		PointerID:    pointerID,
+		X:            x,
+		Y:            y,
+		ScreenWidth:  uint16(width),
+		ScreenHeight: uint16(height),
+		Pressure:     pressure,
+		ActionButton: actionButton,
+		Buttons:      buttons,
+	})
 }
 
 func (c *Client) InjectScroll(x, y int32, hscroll, vscroll int16, buttons uint32) error {
-	buf := make([]byte, lenInjectScroll)
-	buf[0] = byte(CtrlInjectScrollEvent)
-	binary.BigEndian.PutUint32(buf[1:], uint32(x))
-	binary.BigEndian.PutUint32(buf[5:], uint32(y))
-	binary.BigEndian.PutUint16(buf[9:], uint16(c.handshake.Width))
-	binary.BigEndian.PutUint16(buf[11:], uint16(c.handshake.Height))
-	binary.BigEndian.PutUint16(buf[13:], uint16(hscroll))
-	binary.BigEndian.PutUint16(buf[15:], uint16(vscroll))
-	binary.BigEndian.PutUint32(buf[17:], buttons)
-	_, err := c.controlConn.Write(buf)
+	return c.InjectScrollCtx(context.Background(), x, y, hscroll, vscroll, buttons)
+}
+
+func (c *Client) InjectScrollCtx(ctx context.Context, x, y int32, hscroll, vscroll int16, buttons uint32) error {
+	width, height := c.FrameSize()
 
-	return err
+	return c.Send(ctx, &protocol.InjectScrollEvent{
+		X:            x,
+		Y:            y,
+		ScreenWidth:  uint16(width),
+		ScreenHeight: uint16(height),
+		HScroll:      hscroll,
+		VScroll:      vscroll,
+		Buttons:      buttons,
+	})
 }
 
 func (c *Client) BackOrScreenOn(action byte) error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlBackOrScreenOn), action})
+	return c.BackOrScreenOnCtx(context.Background(), action)
+}
 
-	return err
+func (c *Client) BackOrScreenOnCtx(ctx context.Context, action byte) error {
+	return c.Send(ctx, &protocol.BackOrScreenOn{Action: action})
 }
 
 func (c *Client) ExpandNotificationPanel() error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlExpandNotificationPanel)})
+	return c.ExpandNotificationPanelCtx(context.Background())
+}
 
-	return err
+func (c *Client) ExpandNotificationPanelCtx(ctx context.Context) error {
+	return c.Send(ctx, &protocol.ExpandNotificationPanel{})
 }
 
 func (c *Client) ExpandSettingsPanel() error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlExpandSettingsPanel)})
+	return c.ExpandSettingsPanelCtx(context.Background())
+}
 
-	return err
+func (c *Client) ExpandSettingsPanelCtx(ctx context.Context) error {
+	return c.Send(ctx, &protocol.ExpandSettingsPanel{})
 }
 
 func (c *Client) CollapsePanels() error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlCollapsePanels)})
+	return c.CollapsePanelsCtx(context.Background())
+}
 
-	return err
+func (c *Client) CollapsePanelsCtx(ctx context.Context) error {
+	return c.Send(ctx, &protocol.CollapsePanels{})
 }
 
 func (c *Client) GetClipboard(copyKey byte) error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlGetClipboard), copyKey})
+	return c.GetClipboardCtx(context.Background(), copyKey)
+}
 
-	return err
+func (c *Client) GetClipboardCtx(ctx context.Context, copyKey byte) error {
+	return c.Send(ctx, &protocol.GetClipboard{CopyKey: copyKey})
 }
 
 func (c *Client) SetClipboard(sequence uint64, text string, paste bool) error {
-	if !utf8.ValidString(text) || len(text) > maxClipLength {
-		return ErrClipboardTooLong
-	}
-
-	var buf bytes.Buffer
-
-	_ = buf.WriteByte(byte(CtrlSetClipboard))
-	_ = binary.Write(&buf, binary.BigEndian, sequence)
-	_ = buf.WriteByte(boolByte(paste))
-	_ = binary.Write(&buf, binary.BigEndian, uint32(len(text)))
-	_, _ = buf.WriteString(text)
-	_, err := c.controlConn.Write(buf.Bytes())
+	return c.SetClipboardCtx(context.Background(), sequence, text, paste)
+}
 
-	return err
+func (c *Client) SetClipboardCtx(ctx context.Context, sequence uint64, text string, paste bool) error {
+	return c.Send(ctx, &protocol.SetClipboard{
+		Sequence: sequence,
+		Paste:    paste,
+		Text:     text,
+	})
 }
 
 func (c *Client) SetDisplayPower(on bool) error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlSetDisplayPower), boolByte(on)})
+	return c.SetDisplayPowerCtx(context.Background(), on)
+}
 
-	return err
+func (c *Client) SetDisplayPowerCtx(ctx context.Context, on bool) error {
+	return c.Send(ctx, &protocol.SetDisplayPower{On: on})
 }
 
 func (c *Client) RotateDevice() error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlRotateDevice)})
+	return c.RotateDeviceCtx(context.Background())
+}
 
-	return err
+func (c *Client) RotateDeviceCtx(ctx context.Context) error {
+	return c.Send(ctx, &protocol.RotateDevice{})
 }
 
 func (c *Client) UhidCreate(id, vendorID, productID uint16, name string, data []byte) error {
-	if len(name) > 255 {
-		return ErrUhidNameTooLong
-	}
-
-	if len(data) > 0xffff {
-		return ErrUhidDataTooLong
-	}
-
-	var buf bytes.Buffer
-
-	_ = buf.WriteByte(byte(CtrlUhidCreate))
-	_ = binary.Write(&buf, binary.BigEndian, id)
-	_ = binary.Write(&buf, binary.BigEndian, vendorID)
-	_ = binary.Write(&buf, binary.BigEndian, productID)
-	_ = buf.WriteByte(byte(len(name)))
-	_, _ = buf.WriteString(name)
-	_ = binary.Write(&buf, binary.BigEndian, uint16(len(data)))
-	_, _ = buf.Write(data)
-	_, err := c.controlConn.Write(buf.Bytes())
+	return c.UhidCreateCtx(context.Background(), id, vendorID, productID, name, data)
+}
 
-	return err
+func (c *Client) UhidCreateCtx(ctx context.Context, id, vendorID, productID uint16, name string, data []byte) error {
+	return c.Send(ctx, &protocol.UhidCreate{
+		ID:         id,
+		VendorID:   vendorID,
+		ProductID:  productID,
+		Name:       name,
+		ReportDesc: data,
+	})
 }
 
 func (c *Client) UhidInput(id uint16, data []byte) error {
-	if len(data) > 0xffff {
-		return ErrUhidDataTooLong
-	}
-
-	var buf bytes.Buffer
-
-	_ = buf.WriteByte(byte(CtrlUhidInput))
-	_ = binary.Write(&buf, binary.BigEndian, id)
-	_ = binary.Write(&buf, binary.BigEndian, uint16(len(data)))
-	_, _ = buf.Write(data)
-	_, err := c.controlConn.Write(buf.Bytes())
+	return c.UhidInputCtx(context.Background(), id, data)
+}
 
-	return err
+func (c *Client) UhidInputCtx(ctx context.Context, id uint16, data []byte) error {
+	return c.Send(ctx, &protocol.UhidInput{ID: id, Data: data})
 }
 
 func (c *Client) UhidDestroy(id uint16) error {
-	var buf [3]byte
-
-	buf[0] = byte(CtrlUhidDestroy)
-	binary.BigEndian.PutUint16(buf[1:], id)
-	_, err := c.controlConn.Write(buf[:])
+	return c.UhidDestroyCtx(context.Background(), id)
+}
 
-	return err
+func (c *Client) UhidDestroyCtx(ctx context.Context, id uint16) error {
+	return c.Send(ctx, &protocol.UhidDestroy{ID: id})
 }
 
 func (c *Client) OpenHardKeyboardSettings() error {
-	_, err := c.controlConn.Write([]byte{byte(CtrlOpenHardKeyboardSettings)})
+	return c.OpenHardKeyboardSettingsCtx(context.Background())
+}
 
-	return err
+func (c *Client) OpenHardKeyboardSettingsCtx(ctx context.Context) error {
+	return c.Send(ctx, &protocol.OpenHardKeyboardSettings{})
 }
 
 func (c *Client) StartApp(name string) error {
-	if len(name) > 255 {
-		return ErrAppNameTooLong
+	return c.StartAppCtx(context.Background(), name)
+}
+
+func (c *Client) StartAppCtx(ctx context.Context, name string) error {
+	return c.Send(ctx, &protocol.StartApp{Name: name})
+}
+
+func (c *Client) Send(ctx context.Context, msg protocol.ControlMessage) error {
+	return c.send(ctx, msg, nil)
+}
+
+func (c *Client) send(ctx context.Context, msg protocol.ControlMessage, onWrite func()) error {
+	if c.writer == nil {
+		return ErrControlDisabled
 	}
 
-	var buf bytes.Buffer
+	buf, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
 
-	_ = buf.WriteByte(byte(CtrlStartApp))
-	_ = buf.WriteByte(byte(len(name)))
-	_, _ = buf.WriteString(name)
-	_, err := c.controlConn.Write(buf.Bytes())
+	if err := c.writer.send(ctx, buf, onWrite); err != nil {
+		return err
+	}
 
-	return err
-}
+	c.sendMutex.Lock()
+	hooks := c.onSend
+	c.sendMutex.Unlock()
 
-func boolByte(b bool) byte {
-	if b {
-		return 1
+	for _, h := range hooks {
+		h.fn(msg)
 	}
 
-	return 0
+	return nil
 }
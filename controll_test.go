@@ -0,0 +1,23 @@
+package scrcpy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func TestSendTooLong(t *testing.T) {
+	_, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	if err := c.InjectText(strings.Repeat("a", protocol.MaxTextLength+1)); !errors.Is(err, scrcpy.ErrTextTooLong) {
+		t.Fatalf("InjectText = %v, want ErrTextTooLong", err)
+	}
+
+	if err := c.StartApp(strings.Repeat("a", 256)); !errors.Is(err, scrcpy.ErrAppNameTooLong) {
+		t.Fatalf("StartApp = %v, want ErrAppNameTooLong", err)
+	}
+}
@@ -0,0 +1,76 @@
+package scrcpy
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+func readDeviceMessage(r io.Reader) (ControlMessage, error) {
+	m, err := protocol.ReadDeviceMessage(r)
+	if err != nil {
+		return ControlMessage{}, err
+	}
+
+	raw, err := m.MarshalBinary()
+	if err != nil {
+		return ControlMessage{}, fmt.Errorf("%s: %w", m.Type(), err)
+	}
+
+	return ControlMessage{
+		Type:    m.Type(),
+		Payload: raw[1:],
+		Message: m,
+	}, nil
+}
+
+func (m ControlMessage) Clipboard() (string, bool) {
+	msg, ok := m.Message.(*protocol.Clipboard)
+	if !ok {
+		return "", false
+	}
+
+	return msg.Text, true
+}
+
+func (m ControlMessage) AckClipboard() (uint64, bool) {
+	msg, ok := m.Message.(*protocol.AckClipboard)
+	if !ok {
+		return 0, false
+	}
+
+	return msg.Sequence, true
+}
+
+func (m ControlMessage) UhidOutput() (uint16, []byte, bool) {
+	msg, ok := m.Message.(*protocol.UhidOutput)
+	if !ok {
+		return 0, nil, false
+	}
+
+	return msg.ID, msg.Data, true
+}
+
+func (c *Client) dispatchDevice(msg ControlMessage) {
+	switch m := msg.Message.(type) {
+	case *protocol.Clipboard:
+		c.notifyClipboard(m.Text)
+
+		if c.onClipboard != nil {
+			c.onClipboard(m.Text)
+		}
+	case *protocol.AckClipboard:
+		c.notifyAckClipboard(m.Sequence)
+
+		if c.onAckClipboard != nil {
+			c.onAckClipboard(m.Sequence)
+		}
+	case *protocol.UhidOutput:
+		c.notifyUhidOutput(m.ID, m.Data)
+
+		if c.onUhidOutput != nil {
+			c.onUhidOutput(m.ID, m.Data)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package scrcpy_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func TestDeviceMessageFraming(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	handled := make(chan scrcpy.ControlMessage, 16)
+
+	c.SetControlHandler(func(_ context.Context, msg scrcpy.ControlMessage) error {
+		handled <- msg
+
+		return nil
+	})
+
+	serveTest(t, c)
+
+	large := strings.Repeat("0123456789abcdef", 1024) + "tail"
+
+	msgs := []protocol.DeviceMessage{
+		&protocol.Clipboard{Text: large},
+		&protocol.AckClipboard{Sequence: 42},
+		&protocol.UhidOutput{ID: 7, Data: []byte{0x01, 0x02}},
+		&protocol.Clipboard{Text: "short"},
+	}
+
+	for _, msg := range msgs {
+		if err := srv.SendDeviceMessage(msg); err != nil {
+			t.Fatalf("SendDeviceMessage: %v", err)
+		}
+	}
+
+	ctx := testContext(t)
+
+	for i, want := range msgs {
+		var got scrcpy.ControlMessage
+
+		select {
+		case got = <-handled:
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+
+		if got.Type != want.Type() {
+			t.Fatalf("message %d type = %v, want %v", i, got.Type, want.Type())
+		}
+
+		raw, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got.Payload, raw[1:]) {
+			t.Fatalf("message %d payload = %d bytes, want %d", i, len(got.Payload), len(raw)-1)
+		}
+	}
+}
+
+func TestDeviceCallbacks(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	type event struct {
+		kind string
+		text string
+		seq  uint64
+		id   uint16
+		data []byte
+	}
+
+	events := make(chan event, 16)
+
+	c.OnClipboard(func(text string) { events <- event{kind: "clipboard", text: text} })
+	c.OnAckClipboard(func(seq uint64) { events <- event{kind: "ack", seq: seq} })
+	c.OnUhidOutput(func(id uint16, data []byte) { events <- event{kind: "uhid", id: id, data: data} })
+
+	serveTest(t, c)
+
+	msgs := []protocol.DeviceMessage{
+		&protocol.Clipboard{Text: "copied"},
+		&protocol.AckClipboard{Sequence: 42},
+		&protocol.UhidOutput{ID: 7, Data: []byte{0x01, 0x02}},
+	}
+
+	for _, msg := range msgs {
+		if err := srv.SendDeviceMessage(msg); err != nil {
+			t.Fatalf("SendDeviceMessage: %v", err)
+		}
+	}
+
+	ctx := testContext(t)
+	want := []event{
+		{kind: "clipboard", text: "copied"},
+		{kind: "ack", seq: 42},
+		{kind: "uhid", id: 7, data: []byte{0x01, 0x02}},
+	}
+
+	for i, w := range want {
+		select {
+		case got := <-events:
+			if got.kind != w.kind || got.text != w.text || got.seq != w.seq || got.id != w.id || !bytes.Equal(got.data, w.data) {
+				t.Fatalf("event %d = %+v, want %+v", i, got, w)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+}
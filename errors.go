@@ -1,11 +1,30 @@
 package scrcpy
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
 
 var (
-	ErrTextTooLong      = errors.New("inject text > 300 bytes")
-	ErrClipboardTooLong = errors.New("clipboard text too long")
-	ErrUhidDataTooLong  = errors.New("uhid data exceeds 64 KiB")
-	ErrAppNameTooLong   = errors.New("start app name exceeds 255 bytes")
-	ErrUhidNameTooLong  = errors.New("uhid name exceeds 255 bytes")
+	ErrTextTooLong      = protocol.ErrTextTooLong
+	ErrClipboardTooLong = protocol.ErrClipboardTooLong
+	ErrUhidDataTooLong  = protocol.ErrUhidDataTooLong
+	ErrAppNameTooLong   = protocol.ErrAppNameTooLong
+	ErrUhidNameTooLong  = protocol.ErrUhidNameTooLong
+	ErrNoSockets        = errors.New("no sockets enabled")
+	ErrInvalidOption    = errors.New("invalid option")
+	ErrControlDisabled  = errors.New("control socket disabled")
+	ErrStreamConfig     = errors.New("device reported stream configuration error")
+	ErrUnsupportedCodec = errors.New("unsupported codec")
+	ErrServerExited     = errors.New("scrcpy server exited")
+	ErrProtocol         = protocol.ErrProtocol
+	ErrClosed           = errors.New("client closed")
+	ErrPointerActive    = errors.New("pointer already down")
+	ErrPointerInactive  = errors.New("pointer not down")
+	ErrUnknownKey       = errors.New("unknown key")
+	ErrUhidIDInUse      = errors.New("uhid id already in use")
+	ErrUhidClosed       = errors.New("uhid device closed")
+	ErrInvalidConfig    = errors.New("invalid codec config")
+	ErrInvalidUTF8      = errors.New("text is not valid utf-8")
 )
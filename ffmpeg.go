@@ -15,12 +15,25 @@ type FFmpeg struct {
 	cmd    *exec.Cmd
 }
 
-func NewDecoder(ctx context.Context) (*FFmpeg, error) {
+var ffmpegDemuxers = map[Codec]string{
+	CodecH264: "h264",
+	CodecH265: "hevc",
+	CodecAV1:  "obu",
+}
+
+func NewDecoder(ctx context.Context, codec Codec) (*FFmpeg, error) {
+	demuxer, ok := ffmpegDemuxers[codec]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCodec, codec)
+	}
+
 	cmd := exec.CommandContext(ctx,
 		"ffmpeg",
 		"-loglevel", "quiet",
-		"-f", "h264",
+		"-f", demuxer,
 		"-i", "pipe:0",
+		"-autoscale", "0",
+		"-fps_mode", "passthrough",
 		"-pix_fmt", "bgr24",
 		"-f", "rawvideo",
 		"pipe:1",
@@ -51,7 +64,7 @@ func (f *FFmpeg) VideoHandler(r io.Reader) error {
 	_, err := io.Copy(f.stdin, r)
 	_ = f.stdin.Close()
 
-	if errors.Is(err, io.EOF) {
+	if err == nil || errors.Is(err, io.EOF) {
 		return nil
 	}
 
@@ -0,0 +1,495 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+const (
+	h264NalSPS   = 7
+	h265NalSPS   = 33
+	av1ObuSeqHdr = 1
+	av1cMarker   = 0x81
+	av1cLen      = 4
+)
+
+func ParseFrameSize(codec Codec, config []byte) (uint32, uint32, error) {
+	switch codec {
+	case CodecH264:
+		for _, nal := range annexBUnits(config) {
+			if len(nal) > 1 && nal[0]&0x1f == h264NalSPS {
+				return parseH264SPS(unescapeRBSP(nal[1:]))
+			}
+		}
+	case CodecH265:
+		for _, nal := range annexBUnits(config) {
+			if len(nal) > 2 && (nal[0]>>1)&0x3f == h265NalSPS {
+				return parseH265SPS(unescapeRBSP(nal[2:]))
+			}
+		}
+	case CodecAV1:
+		return parseAV1Config(config)
+	default:
+		return 0, 0, fmt.Errorf("%w: %s", ErrUnsupportedCodec, codec)
+	}
+
+	return 0, 0, fmt.Errorf("%w: no sequence parameters", ErrInvalidConfig)
+}
+
+func (c *Client) FrameSize() (uint32, uint32) {
+	size := c.frameSize.Load()
+
+	return uint32(size >> 32), uint32(size)
+}
+
+func (c *Client) OnResize(fn func(width, height uint32)) { c.onResize = fn }
+
+func (c *Client) setFrameSize(width, height uint32) bool {
+	size := uint64(width)<<32 | uint64(height)
+
+	return c.frameSize.Swap(size) != size
+}
+
+func (c *Client) trackFrameSize(next PacketHandler) PacketHandler {
+	codec := c.handshake.CodecID
+
+	if !codec.IsVideo() {
+		return next
+	}
+
+	return func(ctx context.Context, pkt Packet) error {
+		if pkt.IsConfig {
+			if w, h, err := ParseFrameSize(codec, pkt.Data); err == nil && c.setFrameSize(w, h) && c.onResize != nil {
+				c.onResize(w, h)
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		return next(ctx, pkt)
+	}
+}
+
+func annexBUnits(data []byte) [][]byte {
+	var units [][]byte
+
+	start := []byte{0, 0, 1}
+
+	for {
+		i := bytes.Index(data, start)
+		if i < 0 {
+			return units
+		}
+
+		data = data[i+len(start):]
+
+		end := bytes.Index(data, start)
+		if end < 0 {
+			return append(units, data)
+		}
+
+		unit := data[:end]
+
+		if end > 0 && data[end-1] == 0 {
+			unit = data[:end-1]
+		}
+
+		units = append(units, unit)
+		data = data[end:]
+	}
+}
+
+func unescapeRBSP(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	zeros := 0
+
+	for _, b := range data {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+
+			continue
+		}
+
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+
+		out = append(out, b)
+	}
+
+	return out
+}
+
+func parseH264SPS(data []byte) (uint32, uint32, error) {
+	r := &bitReader{data: data}
+
+	profile := r.bits(8)
+	r.skip(16)
+	r.ue()
+
+	chromaFormat := uint32(1)
+	separatePlanes := false
+
+	switch profile {
+	case 44, 83, 86, 100, 110, 118, 122, 128, 134, 135, 138, 139, 244:
+		chromaFormat = r.ue()
+
+		if chromaFormat == 3 {
+			separatePlanes = r.flag()
+		}
+
+		r.ue()
+		r.ue()
+		r.skip(1)
+
+		if r.flag() {
+			lists := 8
+			if chromaFormat == 3 {
+				lists = 12
+			}
+
+			for i := range lists {
+				if !r.flag() {
+					continue
+				}
+
+				size := 16
+				if i >= 6 {
+					size = 64
+				}
+
+				last, next := int32(8), int32(8)
+
+				for range size {
+					if next != 0 {
+						next = (last + r.se() + 256) % 256
+					}
+
+					if next != 0 {
+						last = next
+					}
+				}
+			}
+		}
+	}
+
+	r.ue()
+
+	switch r.ue() {
+	case 0:
+		r.ue()
+	case 1:
+		r.skip(1)
+		r.se()
+		r.se()
+
+		for n := r.ue(); n > 0 && r.err == nil; n-- {
+			r.se()
+		}
+	}
+
+	r.ue()
+	r.skip(1)
+
+	widthMbs := r.ue() + 1
+	heightMapUnits := r.ue() + 1
+	frameMbsOnly := r.bits(1)
+
+	if frameMbsOnly == 0 {
+		r.skip(1)
+	}
+
+	r.skip(1)
+
+	width := widthMbs * 16
+	height := (2 - frameMbsOnly) * heightMapUnits * 16
+
+	if r.flag() {
+		left, right, top, bottom := r.ue(), r.ue(), r.ue(), r.ue()
+
+		cropX, cropY := uint32(1), 2-frameMbsOnly
+
+		if !separatePlanes && chromaFormat != 0 {
+			if chromaFormat != 3 {
+				cropX = 2
+			}
+
+			if chromaFormat == 1 {
+				cropY *= 2
+			}
+		}
+
+		width -= cropX * (left + right)
+		height -= cropY * (top + bottom)
+	}
+
+	return checkFrameSize(r, width, height)
+}
+
+func parseH265SPS(data []byte) (uint32, uint32, error) {
+	r := &bitReader{data: data}
+
+	r.skip(4)
+	subLayers := int(r.bits(3))
+	r.skip(1)
+
+	r.skip(96)
+
+	profilePresent := make([]bool, subLayers)
+	levelPresent := make([]bool, subLayers)
+
+	for i := range subLayers {
+		profilePresent[i] = r.flag()
+		levelPresent[i] = r.flag()
+	}
+
+	if subLayers > 0 {
+		r.skip(2 * (8 - subLayers))
+	}
+
+	for i := range subLayers {
+		if profilePresent[i] {
+			r.skip(88)
+		}
+
+		if levelPresent[i] {
+			r.skip(8)
+		}
+	}
+
+	r.ue()
+
+	chromaFormat := r.ue()
+	separatePlanes := false
+
+	if chromaFormat == 3 {
+		separatePlanes = r.flag()
+	}
+
+	width := r.ue()
+	height := r.ue()
+
+	if r.flag() {
+		left, right, top, bottom := r.ue(), r.ue(), r.ue(), r.ue()
+
+		cropX, cropY := uint32(1), uint32(1)
+
+		if !separatePlanes && (chromaFormat == 1 || chromaFormat == 2) {
+			cropX = 2
+		}
+
+		if !separatePlanes && chromaFormat == 1 {
+			cropY = 2
+		}
+
+		width -= cropX * (left + right)
+		height -= cropY * (top + bottom)
+	}
+
+	return checkFrameSize(r, width, height)
+}
+
+func parseAV1Config(data []byte) (uint32, uint32, error) {
+	if len(data) >= av1cLen && data[0] == av1cMarker {
+		data = data[av1cLen:]
+	}
+
+	for len(data) > 0 {
+		r := &bitReader{data: data}
+
+		r.skip(1)
+		obuType := r.bits(4)
+		extension := r.flag()
+		hasSize := r.flag()
+		r.skip(1)
+
+		if extension {
+			r.skip(8)
+		}
+
+		size := uint64(len(data)) - uint64(r.pos/8)
+
+		if hasSize {
+			size = r.leb128()
+		}
+
+		if r.err != nil || uint64(r.pos/8)+size > uint64(len(data)) {
+			return 0, 0, fmt.Errorf("%w: truncated obu", ErrInvalidConfig)
+		}
+
+		payload := data[r.pos/8 : uint64(r.pos/8)+size]
+
+		if obuType == av1ObuSeqHdr {
+			return parseAV1SequenceHeader(payload)
+		}
+
+		data = data[uint64(r.pos/8)+size:]
+	}
+
+	return 0, 0, fmt.Errorf("%w: no sequence header", ErrInvalidConfig)
+}
+
+func parseAV1SequenceHeader(data []byte) (uint32, uint32, error) {
+	r := &bitReader{data: data}
+
+	r.skip(4)
+
+	if r.flag() {
+		r.skip(5)
+	} else {
+		decoderModel := false
+		bufferDelayLen := 0
+
+		if r.flag() {
+			r.skip(64)
+
+			if r.flag() {
+				r.uvlc()
+			}
+
+			decoderModel = r.flag()
+
+			if decoderModel {
+				bufferDelayLen = int(r.bits(5)) + 1
+				r.skip(42)
+			}
+		}
+
+		displayDelay := r.flag()
+
+		for n := r.bits(5) + 1; n > 0 && r.err == nil; n-- {
+			r.skip(12)
+
+			if r.bits(5) > 7 {
+				r.skip(1)
+			}
+
+			if decoderModel && r.flag() {
+				r.skip(2*bufferDelayLen + 1)
+			}
+
+			if displayDelay && r.flag() {
+				r.skip(4)
+			}
+		}
+	}
+
+	widthBits := int(r.bits(4)) + 1
+	heightBits := int(r.bits(4)) + 1
+	width := r.bits(widthBits) + 1
+	height := r.bits(heightBits) + 1
+
+	return checkFrameSize(r, width, height)
+}
+
+func checkFrameSize(r *bitReader, width, height uint32) (uint32, uint32, error) {
+	if r.err != nil {
+		return 0, 0, r.err
+	}
+
+	if width == 0 || height == 0 || width > 0xffff || height > 0xffff {
+		return 0, 0, fmt.Errorf("%w: frame size %dx%d", ErrInvalidConfig, width, height)
+	}
+
+	return width, height, nil
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *bitReader) bits(n int) uint32 {
+	var v uint32
+
+	for range n {
+		if r.pos >= len(r.data)*8 {
+			r.err = fmt.Errorf("%w: truncated", ErrInvalidConfig)
+
+			return 0
+		}
+
+		v = v<<1 | uint32(r.data[r.pos/8]>>(7-r.pos%8))&1
+		r.pos++
+	}
+
+	return v
+}
+
+func (r *bitReader) skip(n int) {
+	if r.pos+n > len(r.data)*8 {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalidConfig)
+		r.pos = len(r.data) * 8
+
+		return
+	}
+
+	r.pos += n
+}
+
+func (r *bitReader) flag() bool {
+	return r.bits(1) == 1
+}
+
+func (r *bitReader) ue() uint32 {
+	zeros := 0
+
+	for r.bits(1) == 0 {
+		if r.err != nil || zeros >= 31 {
+			r.err = fmt.Errorf("%w: bad exp-golomb code", ErrInvalidConfig)
+
+			return 0
+		}
+
+		zeros++
+	}
+
+	return (1<<zeros - 1) + r.bits(zeros)
+}
+
+func (r *bitReader) se() int32 {
+	v := r.ue()
+
+	if v&1 == 1 {
+		return int32((v + 1) / 2)
+	}
+
+	return -int32(v / 2)
+}
+
+func (r *bitReader) uvlc() uint32 {
+	zeros := 0
+
+	for r.bits(1) == 0 {
+		if r.err != nil || zeros >= 32 {
+			r.err = fmt.Errorf("%w: bad uvlc code", ErrInvalidConfig)
+
+			return 0
+		}
+
+		zeros++
+	}
+
+	return (1<<zeros - 1) + r.bits(zeros)
+}
+
+func (r *bitReader) leb128() uint64 {
+	var v uint64
+
+	for i := range 8 {
+		b := r.bits(8)
+		v |= uint64(b&0x7f) << (7 * i)
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	return v
+}
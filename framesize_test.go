@@ -0,0 +1,109 @@
+package scrcpy_test
+
+import (
+	"errors"
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+)
+
+var h264Cropped1080p = []byte{
+	0x00, 0x00, 0x00, 0x01, 0x67, 0x64, 0x00, 0x28, 0xac, 0xd9, 0x40, 0x78, 0x02, 0x27, 0xe5, 0xc0,
+	0x44, 0x00, 0x00, 0x03, 0x00, 0x04, 0x00, 0x00, 0x03, 0x00, 0xf0, 0x3c, 0x60, 0xc6, 0x58, 0x00,
+	0x00, 0x00, 0x01, 0x68, 0xeb, 0xe3, 0xcb, 0x22, 0xc0,
+}
+
+var h264HighScaling = []byte{
+	0x00, 0x00, 0x00, 0x01, 0x67, 0x64, 0x00, 0x1f, 0xad, 0x82, 0x40, 0xb8, 0x20, 0x05, 0xa8, 0x1e,
+	0x03, 0x90, 0x44, 0x0a, 0x41, 0xd0, 0x27, 0x0b, 0x98, 0x16, 0x30, 0x5a, 0x43, 0x41, 0x50, 0x58,
+	0x0c, 0x44, 0x0e, 0x0b, 0x82, 0xc0, 0x5a, 0x16, 0x0c, 0x8c, 0x0a, 0x04, 0x01, 0x18, 0x32, 0x21,
+	0x15, 0x0a, 0x62, 0x26, 0x28, 0xa0, 0x28, 0x02, 0xdc, 0x80, 0x00, 0x00, 0x00, 0x01, 0x68, 0xeb,
+	0xe3, 0xcb, 0x22, 0xc0,
+}
+
+var h264High444Interlaced = []byte{
+	0x00, 0x00, 0x01, 0x67, 0xf4, 0x00, 0x28, 0x92, 0xda, 0xb4, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x00,
+	0x2d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d,
+	0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0xa0, 0x1e, 0x01, 0x13, 0xf2, 0xa0,
+}
+
+var h265SubLayers = []byte{
+	0x00, 0x00, 0x00, 0x01, 0x42, 0x01, 0x05, 0x01, 0x60, 0x00, 0x00, 0x03, 0x00, 0x90, 0x00, 0x00,
+	0x03, 0x00, 0x00, 0x03, 0x00, 0x7b, 0xd0, 0x00, 0x01, 0x60, 0x00, 0x00, 0x03, 0x00, 0x90, 0x00,
+	0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x5a, 0x5d, 0xa0, 0x03, 0xc0, 0x80, 0x11, 0x07, 0xcb, 0x96,
+	0x57, 0x2b, 0x95, 0xe4, 0x91, 0x26, 0xb2, 0x00, 0x00, 0x00, 0x01, 0x44, 0x01, 0xc1, 0x72, 0xb4,
+	0x62, 0x40,
+}
+
+var h265Main720p = []byte{
+	0x00, 0x00, 0x00, 0x01, 0x40, 0x01, 0x0c, 0x01, 0xff, 0xff, 0x01, 0x60, 0x00, 0x00, 0x03, 0x00,
+	0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x7b, 0x95, 0x98, 0x09, 0x00, 0x00, 0x00, 0x01,
+	0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03, 0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+	0x00, 0x7b, 0xa0, 0x02, 0x80, 0x80, 0x2d, 0x16, 0x59, 0x5e, 0x49, 0x12, 0x6b, 0x20, 0x00, 0x00,
+	0x00, 0x01, 0x44, 0x01, 0xc1, 0x72, 0xb4, 0x62, 0x40,
+}
+
+var av1OBUs = []byte{
+	0x12, 0x00, 0x0a, 0x0b, 0x00, 0x00, 0x00, 0x42, 0xae, 0x1b, 0xca, 0xfa, 0x7f, 0xc0, 0x30,
+}
+
+var av1Config = []byte{
+	0x81, 0x08, 0x0c, 0x00, 0x0a, 0x0b, 0x00, 0x00, 0x00, 0x42, 0xae, 0x1b, 0xca, 0xfa, 0x7f, 0xc0,
+	0x30,
+}
+
+var av1TimingInfo = []byte{
+	0x81, 0x09, 0x0c, 0x00, 0x0a, 0x20, 0x04, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0xf3, 0xbc,
+	0x00, 0x00, 0x00, 0x06, 0xf6, 0x84, 0x40, 0xd2, 0x81, 0xf4, 0x03, 0xe8, 0x32, 0x20, 0x25, 0x2a,
+	0xbb, 0xfc, 0x37, 0x4f, 0xf8, 0x06,
+}
+
+func TestParseFrameSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		codec         scrcpy.Codec
+		config        []byte
+		width, height uint32
+		err           error
+	}{
+		{name: "h264 cropped 1080p", codec: scrcpy.CodecH264, config: h264Cropped1080p, width: 1920, height: 1080},
+		{name: "h264 high scaling lists", codec: scrcpy.CodecH264, config: h264HighScaling, width: 1280, height: 720},
+		{name: "h264 high 4:4:4 interlaced", codec: scrcpy.CodecH264, config: h264High444Interlaced, width: 1920, height: 1080},
+		{name: "h265 main 720p", codec: scrcpy.CodecH265, config: h265Main720p, width: 1280, height: 720},
+		{name: "h265 sub-layers", codec: scrcpy.CodecH265, config: h265SubLayers, width: 1920, height: 1080},
+		{name: "av1 obus", codec: scrcpy.CodecAV1, config: av1OBUs, width: 1080, height: 2400},
+		{name: "av1 av1C", codec: scrcpy.CodecAV1, config: av1Config, width: 1080, height: 2400},
+		{name: "av1 timing info", codec: scrcpy.CodecAV1, config: av1TimingInfo, width: 1920, height: 1080},
+		{name: "h264 truncated sps", codec: scrcpy.CodecH264, config: h264Cropped1080p[:14], err: scrcpy.ErrInvalidConfig},
+		{name: "h264 truncated scaling list", codec: scrcpy.CodecH264, config: h264HighScaling[:24], err: scrcpy.ErrInvalidConfig},
+		{name: "h264 no sps", codec: scrcpy.CodecH264, config: h264Cropped1080p[31:], err: scrcpy.ErrInvalidConfig},
+		{name: "h265 truncated sub-layers", codec: scrcpy.CodecH265, config: h265SubLayers[:30], err: scrcpy.ErrInvalidConfig},
+		{name: "h265 vps only", codec: scrcpy.CodecH265, config: h265Main720p[:28], err: scrcpy.ErrInvalidConfig},
+		{name: "av1 truncated obu", codec: scrcpy.CodecAV1, config: av1OBUs[:10], err: scrcpy.ErrInvalidConfig},
+		{name: "av1 truncated sequence header", codec: scrcpy.CodecAV1, config: []byte{0x0a, 0x02, 0x00, 0x00}, err: scrcpy.ErrInvalidConfig},
+		{name: "av1 av1C only", codec: scrcpy.CodecAV1, config: av1Config[:4], err: scrcpy.ErrInvalidConfig},
+		{name: "empty", codec: scrcpy.CodecH264, err: scrcpy.ErrInvalidConfig},
+		{name: "audio codec", codec: scrcpy.CodecOpus, config: av1OBUs, err: scrcpy.ErrUnsupportedCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := scrcpy.ParseFrameSize(tt.codec, tt.config)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("ParseFrameSize = %dx%d, %v, want %v", width, height, err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseFrameSize: %v", err)
+			}
+
+			if width != tt.width || height != tt.height {
+				t.Fatalf("ParseFrameSize = %dx%d, want %dx%d", width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package gestures
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+)
+
+const (
+	DefaultPointerID = 0x100
+	TapDuration      = 50 * time.Millisecond
+	DoubleTapGap     = 100 * time.Millisecond
+	FlingDuration    = 80 * time.Millisecond
+	FlingSteps       = 4
+	releaseTimeout   = time.Second
+)
+
+var ErrInvalidGesture = errors.New("invalid gesture")
+
+type Toucher interface {
+	InjectTouchCtx(ctx context.Context, action byte, pointerID uint64, x, y uint32, pressure uint16, actionButton, buttons uint32) error
+}
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type Path []Point
+
+type Gestures struct {
+	Target    Toucher
+	PointerID uint64
+	Pressure  uint16
+}
+
+func New(t Toucher) *Gestures {
+	return &Gestures{
+		Target:    t,
+		PointerID: DefaultPointerID,
+		Pressure:  0xffff,
+	}
+}
+
+func (g *Gestures) Tap(ctx context.Context, p Point) error {
+	return g.LongPress(ctx, p, TapDuration)
+}
+
+func (g *Gestures) DoubleTap(ctx context.Context, p Point) error {
+	if err := g.Tap(ctx, p); err != nil {
+		return err
+	}
+
+	if err := sleep(ctx, DoubleTapGap); err != nil {
+		return err
+	}
+
+	return g.Tap(ctx, p)
+}
+
+func (g *Gestures) LongPress(ctx context.Context, p Point, d time.Duration) error {
+	return g.Perform(ctx, []Path{{p}}, d, 0, 0)
+}
+
+func (g *Gestures) Swipe(ctx context.Context, from, to Point, d time.Duration, steps int) error {
+	return g.Perform(ctx, []Path{{from, to}}, 0, d, steps)
+}
+
+func (g *Gestures) Drag(ctx context.Context, from, to Point, hold, d time.Duration, steps int) error {
+	return g.Perform(ctx, []Path{{from, to}}, hold, d, steps)
+}
+
+func (g *Gestures) Fling(ctx context.Context, from, to Point) error {
+	return g.Swipe(ctx, from, to, FlingDuration, FlingSteps)
+}
+
+func (g *Gestures) Pinch(ctx context.Context, center Point, fromSpan, toSpan float64, d time.Duration, steps int) error {
+	a := Path{{center.X - fromSpan/2, center.Y}, {center.X - toSpan/2, center.Y}}
+	b := Path{{center.X + fromSpan/2, center.Y}, {center.X + toSpan/2, center.Y}}
+
+	return g.Perform(ctx, []Path{a, b}, 0, d, steps)
+}
+
+func (g *Gestures) Zoom(ctx context.Context, center Point, span, factor float64, d time.Duration, steps int) error {
+	if factor <= 0 {
+		return ErrInvalidGesture
+	}
+
+	return g.Pinch(ctx, center, span, span*factor, d, steps)
+}
+
+func (g *Gestures) Rotate(ctx context.Context, center Point, radius, degrees float64, d time.Duration, steps int) error {
+	steps = max(steps, 1)
+	a := make(Path, 0, steps+1)
+	b := make(Path, 0, steps+1)
+
+	for i := 0; i <= steps; i++ {
+		angle := degrees * math.Pi / 180 * float64(i) / float64(steps)
+		dx, dy := radius*math.Cos(angle), radius*math.Sin(angle)
+		a = append(a, Point{center.X - dx, center.Y - dy})
+		b = append(b, Point{center.X + dx, center.Y + dy})
+	}
+
+	return g.Perform(ctx, []Path{a, b}, 0, d, steps)
+}
+
+func (g *Gestures) Perform(ctx context.Context, paths []Path, hold, d time.Duration, steps int) (err error) {
+	if len(paths) == 0 {
+		return ErrInvalidGesture
+	}
+
+	for _, p := range paths {
+		if len(p) == 0 {
+			return ErrInvalidGesture
+		}
+	}
+
+	steps = max(steps, 1)
+	pressed := make([]bool, len(paths))
+	pos := make([]Point, len(paths))
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancel()
+
+		for i, down := range pressed {
+			if down {
+				_ = g.touch(rctx, scrcpy.ActionUp, i, pos[i], 0)
+			}
+		}
+	}()
+
+	for i, p := range paths {
+		pos[i] = p[0]
+
+		if err := g.touch(ctx, scrcpy.ActionDown, i, pos[i], g.Pressure); err != nil {
+			return err
+		}
+
+		pressed[i] = true
+	}
+
+	if err := sleep(ctx, hold); err != nil {
+		return err
+	}
+
+	moving := false
+
+	for _, p := range paths {
+		moving = moving || len(p) > 1
+	}
+
+	for s := 1; moving && s <= steps; s++ {
+		if err := sleep(ctx, d/time.Duration(steps)); err != nil {
+			return err
+		}
+
+		for i, p := range paths {
+			pos[i] = p.At(float64(s) / float64(steps))
+
+			if err := g.touch(ctx, scrcpy.ActionMove, i, pos[i], g.Pressure); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range paths {
+		if err := g.touch(ctx, scrcpy.ActionUp, i, pos[i], 0); err != nil {
+			return err
+		}
+
+		pressed[i] = false
+	}
+
+	return nil
+}
+
+func (p Path) At(t float64) Point {
+	if len(p) == 1 || t <= 0 {
+		return p[0]
+	}
+
+	if t >= 1 {
+		return p[len(p)-1]
+	}
+
+	total := p.length()
+	if total == 0 {
+		return p[0]
+	}
+
+	target := total * t
+
+	for i := 1; i < len(p); i++ {
+		seg := dist(p[i-1], p[i])
+		if seg >= target && seg > 0 {
+			f := target / seg
+
+			return Point{
+				X: p[i-1].X + (p[i].X-p[i-1].X)*f,
+				Y: p[i-1].Y + (p[i].Y-p[i-1].Y)*f,
+			}
+		}
+
+		target -= seg
+	}
+
+	return p[len(p)-1]
+}
+
+func (p Path) length() float64 {
+	var total float64
+
+	for i := 1; i < len(p); i++ {
+		total += dist(p[i-1], p[i])
+	}
+
+	return total
+}
+
+func (g *Gestures) touch(ctx context.Context, action byte, finger int, p Point, pressure uint16) error {
+	return g.Target.InjectTouchCtx(ctx, action, g.PointerID+uint64(finger), coord(p.X), coord(p.Y), pressure, 0, 0)
+}
+
+func coord(v float64) uint32 {
+	if v <= 0 {
+		return 0
+	}
+
+	return uint32(math.Round(v))
+}
+
+func dist(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,257 @@
+package gestures_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/gestures"
+)
+
+var errInject = errors.New("inject failed")
+
+type touchEvent struct {
+	action byte
+	id     uint64
+	x, y   uint32
+	at     time.Duration
+}
+
+type fakeToucher struct {
+	mutex  sync.Mutex
+	start  time.Time
+	events []touchEvent
+	fail   func(action byte, id uint64) bool
+}
+
+func newFakeToucher() *fakeToucher {
+	return &fakeToucher{start: time.Now()}
+}
+
+func (f *fakeToucher) InjectTouchCtx(ctx context.Context, action byte, pointerID uint64, x, y uint32, _ uint16, _, _ uint32) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if f.fail != nil && f.fail(action, pointerID) {
+		return errInject
+	}
+
+	f.events = append(f.events, touchEvent{action, pointerID, x, y, time.Since(f.start)})
+
+	return nil
+}
+
+func (f *fakeToucher) log() []touchEvent {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return slices.Clone(f.events)
+}
+
+func (f *fakeToucher) down() []uint64 {
+	var ids []uint64
+
+	for _, ev := range f.log() {
+		switch ev.action {
+		case scrcpy.ActionDown:
+			ids = append(ids, ev.id)
+		case scrcpy.ActionUp:
+			ids = slices.DeleteFunc(ids, func(id uint64) bool { return id == ev.id })
+		}
+	}
+
+	return ids
+}
+
+func TestSwipe(t *testing.T) {
+	f := newFakeToucher()
+	g := gestures.New(f)
+
+	if err := g.Swipe(context.Background(), gestures.Point{X: 0, Y: 100}, gestures.Point{X: 400, Y: 100}, 200*time.Millisecond, 4); err != nil {
+		t.Fatalf("Swipe: %v", err)
+	}
+
+	id := uint64(gestures.DefaultPointerID)
+	want := []touchEvent{
+		{action: scrcpy.ActionDown, id: id, x: 0, y: 100},
+		{action: scrcpy.ActionMove, id: id, x: 100, y: 100},
+		{action: scrcpy.ActionMove, id: id, x: 200, y: 100},
+		{action: scrcpy.ActionMove, id: id, x: 300, y: 100},
+		{action: scrcpy.ActionMove, id: id, x: 400, y: 100},
+		{action: scrcpy.ActionUp, id: id, x: 400, y: 100},
+	}
+
+	got := f.log()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(got), len(want), got)
+	}
+
+	for i, w := range want {
+		ev := got[i]
+		if ev.action != w.action || ev.id != w.id || ev.x != w.x || ev.y != w.y {
+			t.Errorf("event %d = %+v, want %+v", i, ev, w)
+		}
+	}
+
+	for i := 1; i <= 4; i++ {
+		if step := got[i].at - got[i-1].at; step < 40*time.Millisecond {
+			t.Errorf("step %d took %v, want ~50ms", i, step)
+		}
+	}
+
+	if total := got[len(got)-1].at - got[0].at; total < 200*time.Millisecond || total > 400*time.Millisecond {
+		t.Errorf("swipe took %v, want ~200ms", total)
+	}
+}
+
+func TestPinchOrder(t *testing.T) {
+	f := newFakeToucher()
+	g := gestures.New(f)
+
+	if err := g.Pinch(context.Background(), gestures.Point{X: 500, Y: 500}, 400, 200, 0, 1); err != nil {
+		t.Fatalf("Pinch: %v", err)
+	}
+
+	a, b := uint64(gestures.DefaultPointerID), uint64(gestures.DefaultPointerID+1)
+	want := []touchEvent{
+		{action: scrcpy.ActionDown, id: a, x: 300, y: 500},
+		{action: scrcpy.ActionDown, id: b, x: 700, y: 500},
+		{action: scrcpy.ActionMove, id: a, x: 400, y: 500},
+		{action: scrcpy.ActionMove, id: b, x: 600, y: 500},
+		{action: scrcpy.ActionUp, id: a, x: 400, y: 500},
+		{action: scrcpy.ActionUp, id: b, x: 600, y: 500},
+	}
+
+	got := f.log()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(got), len(want), got)
+	}
+
+	for i, w := range want {
+		ev := got[i]
+		if ev.action != w.action || ev.id != w.id || ev.x != w.x || ev.y != w.y {
+			t.Errorf("event %d = %+v, want %+v", i, ev, w)
+		}
+	}
+}
+
+func TestLongPressHold(t *testing.T) {
+	f := newFakeToucher()
+	g := gestures.New(f)
+
+	if err := g.LongPress(context.Background(), gestures.Point{X: 10, Y: 20}, 150*time.Millisecond); err != nil {
+		t.Fatalf("LongPress: %v", err)
+	}
+
+	got := f.log()
+	if len(got) != 2 || got[0].action != scrcpy.ActionDown || got[1].action != scrcpy.ActionUp {
+		t.Fatalf("events = %+v", got)
+	}
+
+	if hold := got[1].at - got[0].at; hold < 150*time.Millisecond {
+		t.Fatalf("held for %v, want at least 150ms", hold)
+	}
+}
+
+func TestCancelReleases(t *testing.T) {
+	tests := []struct {
+		name    string
+		perform func(ctx context.Context, g *gestures.Gestures) error
+	}{
+		{
+			name: "hold",
+			perform: func(ctx context.Context, g *gestures.Gestures) error {
+				return g.LongPress(ctx, gestures.Point{X: 1, Y: 1}, time.Minute)
+			},
+		},
+		{
+			name: "move",
+			perform: func(ctx context.Context, g *gestures.Gestures) error {
+				return g.Pinch(ctx, gestures.Point{X: 500, Y: 500}, 400, 100, time.Minute, 10)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeToucher()
+			g := gestures.New(f)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			if err := tt.perform(ctx, g); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want DeadlineExceeded", err)
+			}
+
+			if down := f.down(); len(down) != 0 {
+				t.Fatalf("pointers still down: %v", down)
+			}
+		})
+	}
+}
+
+func TestFailedUpReleasesRemaining(t *testing.T) {
+	f := newFakeToucher()
+	g := gestures.New(f)
+
+	failing := uint64(gestures.DefaultPointerID + 1)
+	failed := false
+
+	f.fail = func(action byte, id uint64) bool {
+		if action == scrcpy.ActionUp && id == failing && !failed {
+			failed = true
+
+			return true
+		}
+
+		return false
+	}
+
+	path := func(x float64) gestures.Path { return gestures.Path{{X: x, Y: 0}} }
+
+	if err := g.Perform(context.Background(), []gestures.Path{path(0), path(100), path(200)}, 0, 0, 1); !errors.Is(err, errInject) {
+		t.Fatalf("Perform = %v, want errInject", err)
+	}
+
+	var ups []uint64
+
+	for _, ev := range f.log() {
+		if ev.action == scrcpy.ActionUp {
+			ups = append(ups, ev.id)
+		}
+	}
+
+	base := uint64(gestures.DefaultPointerID)
+	if want := []uint64{base, base + 1, base + 2}; !slices.Equal(ups, want) {
+		t.Fatalf("ups = %v, want %v", ups, want)
+	}
+
+	if down := f.down(); len(down) != 0 {
+		t.Fatalf("pointers still down: %v", down)
+	}
+}
+
+func TestInvalidGesture(t *testing.T) {
+	g := gestures.New(newFakeToucher())
+
+	if err := g.Perform(context.Background(), nil, 0, 0, 1); !errors.Is(err, gestures.ErrInvalidGesture) {
+		t.Errorf("Perform(nil) = %v, want ErrInvalidGesture", err)
+	}
+
+	if err := g.Perform(context.Background(), []gestures.Path{{}}, 0, 0, 1); !errors.Is(err, gestures.ErrInvalidGesture) {
+		t.Errorf("Perform(empty path) = %v, want ErrInvalidGesture", err)
+	}
+
+	if err := g.Zoom(context.Background(), gestures.Point{}, 100, 0, 0, 1); !errors.Is(err, gestures.ErrInvalidGesture) {
+		t.Errorf("Zoom(0) = %v, want ErrInvalidGesture", err)
+	}
+}
@@ -0,0 +1,132 @@
+package hid
+
+type Builder struct {
+	buf []byte
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) Bytes() []byte {
+	return append([]byte(nil), b.buf...)
+}
+
+func (b *Builder) UsagePage(page uint16) *Builder {
+	return b.unsigned(TypeGlobal, TagUsagePage, uint32(page))
+}
+
+func (b *Builder) Usage(id uint16) *Builder {
+	return b.unsigned(TypeLocal, TagUsage, uint32(id))
+}
+
+func (b *Builder) ExtendedUsage(u Usage) *Builder {
+	return b.raw(TypeLocal, TagUsage, []byte{byte(u), byte(u >> 8), byte(u >> 16), byte(u >> 24)})
+}
+
+func (b *Builder) UsageMinimum(id uint16) *Builder {
+	return b.unsigned(TypeLocal, TagUsageMinimum, uint32(id))
+}
+
+func (b *Builder) UsageMaximum(id uint16) *Builder {
+	return b.unsigned(TypeLocal, TagUsageMaximum, uint32(id))
+}
+
+func (b *Builder) UsageRange(lo, hi uint16) *Builder {
+	return b.UsageMinimum(lo).UsageMaximum(hi)
+}
+
+func (b *Builder) LogicalMinimum(v int32) *Builder {
+	return b.signed(TypeGlobal, TagLogicalMinimum, v)
+}
+
+func (b *Builder) LogicalMaximum(v int32) *Builder {
+	return b.signed(TypeGlobal, TagLogicalMaximum, v)
+}
+
+func (b *Builder) LogicalRange(lo, hi int32) *Builder {
+	return b.LogicalMinimum(lo).LogicalMaximum(hi)
+}
+
+func (b *Builder) PhysicalMinimum(v int32) *Builder {
+	return b.signed(TypeGlobal, TagPhysicalMinimum, v)
+}
+
+func (b *Builder) PhysicalMaximum(v int32) *Builder {
+	return b.signed(TypeGlobal, TagPhysicalMaximum, v)
+}
+
+func (b *Builder) Unit(u uint32) *Builder {
+	return b.unsigned(TypeGlobal, TagUnit, u)
+}
+
+func (b *Builder) UnitExponent(e int8) *Builder {
+	return b.unsigned(TypeGlobal, TagUnitExponent, uint32(e)&0xf)
+}
+
+func (b *Builder) ReportSize(bits uint32) *Builder {
+	return b.unsigned(TypeGlobal, TagReportSize, bits)
+}
+
+func (b *Builder) ReportCount(n uint32) *Builder {
+	return b.unsigned(TypeGlobal, TagReportCount, n)
+}
+
+func (b *Builder) ReportID(id uint8) *Builder {
+	return b.unsigned(TypeGlobal, TagReportID, uint32(id))
+}
+
+func (b *Builder) Push() *Builder {
+	return b.raw(TypeGlobal, TagPush, nil)
+}
+
+func (b *Builder) Pop() *Builder {
+	return b.raw(TypeGlobal, TagPop, nil)
+}
+
+func (b *Builder) Collection(kind CollectionKind) *Builder {
+	return b.unsigned(TypeMain, TagCollection, uint32(kind))
+}
+
+func (b *Builder) EndCollection() *Builder {
+	return b.raw(TypeMain, TagEndCollection, nil)
+}
+
+func (b *Builder) Input(flags MainFlags) *Builder {
+	return b.unsigned(TypeMain, TagInput, uint32(flags))
+}
+
+func (b *Builder) Output(flags MainFlags) *Builder {
+	return b.unsigned(TypeMain, TagOutput, uint32(flags))
+}
+
+func (b *Builder) Feature(flags MainFlags) *Builder {
+	return b.unsigned(TypeMain, TagFeature, uint32(flags))
+}
+
+func (b *Builder) Padding(kind ReportKind, bits uint32) *Builder {
+	b.ReportSize(bits).ReportCount(1)
+
+	switch kind {
+	case Output:
+		return b.Output(Constant)
+	case Feature:
+		return b.Feature(Constant)
+	}
+
+	return b.Input(Constant)
+}
+
+func (b *Builder) unsigned(typ ItemType, tag uint8, v uint32) *Builder {
+	return b.raw(typ, tag, unsignedData(v))
+}
+
+func (b *Builder) signed(typ ItemType, tag uint8, v int32) *Builder {
+	return b.raw(typ, tag, signedData(v))
+}
+
+func (b *Builder) raw(typ ItemType, tag uint8, data []byte) *Builder {
+	b.buf = append(b.buf, encodeItem(typ, tag, data)...)
+
+	return b
+}
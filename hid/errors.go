@@ -0,0 +1,11 @@
+package hid
+
+import "errors"
+
+var (
+	ErrInvalidDescriptor = errors.New("invalid hid report descriptor")
+	ErrUnknownUsage      = errors.New("usage not in report")
+	ErrUnknownReport     = errors.New("unknown report id")
+	ErrShortReport       = errors.New("report too short")
+	ErrValueRange        = errors.New("value outside logical range")
+)
@@ -0,0 +1,214 @@
+package hid
+
+import "fmt"
+
+type ItemType uint8
+
+const (
+	TypeMain   ItemType = 0
+	TypeGlobal ItemType = 1
+	TypeLocal  ItemType = 2
+)
+
+const (
+	TagInput         = 0x8
+	TagOutput        = 0x9
+	TagCollection    = 0xa
+	TagFeature       = 0xb
+	TagEndCollection = 0xc
+)
+
+const (
+	TagUsagePage       = 0x0
+	TagLogicalMinimum  = 0x1
+	TagLogicalMaximum  = 0x2
+	TagPhysicalMinimum = 0x3
+	TagPhysicalMaximum = 0x4
+	TagUnitExponent    = 0x5
+	TagUnit            = 0x6
+	TagReportSize      = 0x7
+	TagReportID        = 0x8
+	TagReportCount     = 0x9
+	TagPush            = 0xa
+	TagPop             = 0xb
+)
+
+const (
+	TagUsage        = 0x0
+	TagUsageMinimum = 0x1
+	TagUsageMaximum = 0x2
+)
+
+const longItemPrefix = 0xfe
+
+type CollectionKind uint8
+
+const (
+	CollectionPhysical      CollectionKind = 0x00
+	CollectionApplication   CollectionKind = 0x01
+	CollectionLogical       CollectionKind = 0x02
+	CollectionReport        CollectionKind = 0x03
+	CollectionNamedArray    CollectionKind = 0x04
+	CollectionUsageSwitch   CollectionKind = 0x05
+	CollectionUsageModifier CollectionKind = 0x06
+)
+
+type MainFlags uint32
+
+const (
+	Data          MainFlags = 0
+	Constant      MainFlags = 1 << 0
+	Array         MainFlags = 0
+	Variable      MainFlags = 1 << 1
+	Absolute      MainFlags = 0
+	Relative      MainFlags = 1 << 2
+	Wrap          MainFlags = 1 << 3
+	NonLinear     MainFlags = 1 << 4
+	NoPreferred   MainFlags = 1 << 5
+	NullState     MainFlags = 1 << 6
+	Volatile      MainFlags = 1 << 7
+	BufferedBytes MainFlags = 1 << 8
+)
+
+type ReportKind uint8
+
+const (
+	Input ReportKind = iota
+	Output
+	Feature
+)
+
+func (k ReportKind) String() string {
+	switch k {
+	case Input:
+		return "input"
+	case Output:
+		return "output"
+	case Feature:
+		return "feature"
+	}
+
+	return fmt.Sprintf("ReportKind(%d)", uint8(k))
+}
+
+const (
+	PageGenericDesktop = 0x01
+	PageSimulation     = 0x02
+	PageKeyboard       = 0x07
+	PageLED            = 0x08
+	PageButton         = 0x09
+	PageConsumer       = 0x0c
+	PageDigitizer      = 0x0d
+	PageBarcodeScanner = 0x8c
+)
+
+type Usage uint32
+
+func MakeUsage(page, id uint16) Usage {
+	return Usage(uint32(page)<<16 | uint32(id))
+}
+
+func (u Usage) Page() uint16 { return uint16(u >> 16) }
+
+func (u Usage) ID() uint16 { return uint16(u) }
+
+func (u Usage) String() string {
+	return fmt.Sprintf("%04x:%04x", u.Page(), u.ID())
+}
+
+type item struct {
+	typ  ItemType
+	tag  uint8
+	data []byte
+}
+
+func (it item) unsigned() uint32 {
+	var v uint32
+
+	for i, b := range it.data {
+		v |= uint32(b) << (8 * i)
+	}
+
+	return v
+}
+
+func (it item) signed() int32 {
+	v := it.unsigned()
+
+	switch len(it.data) {
+	case 1:
+		return int32(int8(v))
+	case 2:
+		return int32(int16(v))
+	}
+
+	return int32(v)
+}
+
+func encodeItem(typ ItemType, tag uint8, data []byte) []byte {
+	code := len(data)
+	if code == 4 {
+		code = 3
+	}
+
+	return append([]byte{tag<<4 | uint8(typ)<<2 | uint8(code)}, data...)
+}
+
+func unsignedData(v uint32) []byte {
+	switch {
+	case v <= 0xff:
+		return []byte{byte(v)}
+	case v <= 0xffff:
+		return []byte{byte(v), byte(v >> 8)}
+	}
+
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+}
+
+func signedData(v int32) []byte {
+	switch {
+	case v >= -0x80 && v <= 0x7f:
+		return []byte{byte(v)}
+	case v >= -0x8000 && v <= 0x7fff:
+		return []byte{byte(v), byte(v >> 8)}
+	}
+
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+}
+
+func readItems(desc []byte) ([]item, error) {
+	var items []item
+
+	for i := 0; i < len(desc); {
+		prefix := desc[i]
+
+		if prefix == longItemPrefix {
+			if i+2 >= len(desc) {
+				return nil, fmt.Errorf("%w: truncated long item at %d", ErrInvalidDescriptor, i)
+			}
+
+			i += 3 + int(desc[i+1])
+
+			continue
+		}
+
+		size := int(prefix & 0x3)
+		if size == 3 {
+			size = 4
+		}
+
+		if i+1+size > len(desc) {
+			return nil, fmt.Errorf("%w: truncated item at %d", ErrInvalidDescriptor, i)
+		}
+
+		items = append(items, item{
+			typ:  ItemType(prefix >> 2 & 0x3),
+			tag:  prefix >> 4,
+			data: desc[i+1 : i+1+size],
+		})
+
+		i += 1 + size
+	}
+
+	return items, nil
+}
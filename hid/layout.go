@@ -0,0 +1,237 @@
+package hid
+
+import (
+	"fmt"
+	"slices"
+)
+
+const maxReportLength = 4096
+
+type Field struct {
+	Kind       ReportKind
+	ReportID   uint8
+	Flags      MainFlags
+	Usages     []Usage
+	Offset     int
+	Size       int
+	Count      int
+	LogicalMin int64
+	LogicalMax int64
+}
+
+type Layout struct {
+	Fields    []Field
+	UsesIDs   bool
+	reportLen map[reportKey]int
+}
+
+type reportKey struct {
+	kind ReportKind
+	id   uint8
+}
+
+type globalState struct {
+	usagePage   uint16
+	logicalMin  item
+	logicalMax  item
+	reportSize  int
+	reportCount int
+	reportID    uint8
+}
+
+type localState struct {
+	usages   []Usage
+	usageMin Usage
+	usageMax Usage
+	hasRange bool
+}
+
+func Parse(desc []byte) (*Layout, error) {
+	items, err := readItems(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{reportLen: make(map[reportKey]int)}
+	bits := make(map[reportKey]int)
+
+	var (
+		global globalState
+		local  localState
+		stack  []globalState
+		depth  int
+	)
+
+	for _, it := range items {
+		switch it.typ {
+		case TypeGlobal:
+			switch it.tag {
+			case TagUsagePage:
+				global.usagePage = uint16(it.unsigned())
+			case TagLogicalMinimum:
+				global.logicalMin = it
+			case TagLogicalMaximum:
+				global.logicalMax = it
+			case TagReportSize:
+				global.reportSize = int(it.unsigned())
+			case TagReportCount:
+				global.reportCount = int(it.unsigned())
+			case TagReportID:
+				if it.unsigned() == 0 || it.unsigned() > 0xff {
+					return nil, fmt.Errorf("%w: report id %d", ErrInvalidDescriptor, it.unsigned())
+				}
+
+				global.reportID = uint8(it.unsigned())
+				l.UsesIDs = true
+			case TagPush:
+				stack = append(stack, global)
+			case TagPop:
+				if len(stack) == 0 {
+					return nil, fmt.Errorf("%w: pop without push", ErrInvalidDescriptor)
+				}
+
+				global = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case TypeLocal:
+			switch it.tag {
+			case TagUsage:
+				local.usages = append(local.usages, global.usage(it))
+			case TagUsageMinimum:
+				local.usageMin = global.usage(it)
+				local.hasRange = true
+			case TagUsageMaximum:
+				local.usageMax = global.usage(it)
+				local.hasRange = true
+			}
+		case TypeMain:
+			switch it.tag {
+			case TagCollection:
+				depth++
+			case TagEndCollection:
+				if depth == 0 {
+					return nil, fmt.Errorf("%w: unbalanced end collection", ErrInvalidDescriptor)
+				}
+
+				depth--
+			case TagInput, TagOutput, TagFeature:
+				kind := mainKind(it.tag)
+				key := reportKey{kind: kind, id: global.reportID}
+
+				f := Field{
+					Kind:     kind,
+					ReportID: global.reportID,
+					Flags:    MainFlags(it.unsigned()),
+					Offset:   bits[key],
+					Size:     global.reportSize,
+					Count:    global.reportCount,
+				}
+
+				if f.Size > maxReportLength*8 || f.Count > maxReportLength*8 || bits[key]+f.Size*f.Count > maxReportLength*8 {
+					return nil, fmt.Errorf("%w: %s report %d longer than %d bytes", ErrInvalidDescriptor, kind, key.id, maxReportLength)
+				}
+
+				f.LogicalMin, f.LogicalMax = global.logicalRange()
+				f.Usages = local.expand(f)
+
+				bits[key] += f.Size * f.Count
+				l.Fields = append(l.Fields, f)
+			}
+
+			local = localState{}
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unterminated collection", ErrInvalidDescriptor)
+	}
+
+	for key, n := range bits {
+		l.reportLen[key] = (n + 7) / 8
+
+		if l.ReportLength(key.kind, key.id) > maxReportLength {
+			return nil, fmt.Errorf("%w: %s report %d longer than %d bytes", ErrInvalidDescriptor, key.kind, key.id, maxReportLength)
+		}
+	}
+
+	return l, nil
+}
+
+func (l *Layout) ReportLength(kind ReportKind, id uint8) int {
+	n, ok := l.reportLen[reportKey{kind: kind, id: id}]
+	if !ok {
+		return 0
+	}
+
+	if l.UsesIDs {
+		n++
+	}
+
+	return n
+}
+
+func (l *Layout) ReportIDs(kind ReportKind) []uint8 {
+	var ids []uint8
+
+	for key := range l.reportLen {
+		if key.kind == kind {
+			ids = append(ids, key.id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+func (f *Field) IsConstant() bool { return f.Flags&Constant != 0 }
+
+func (f *Field) IsVariable() bool { return f.Flags&Variable != 0 }
+
+func (f *Field) IsRelative() bool { return f.Flags&Relative != 0 }
+
+func (g *globalState) usage(it item) Usage {
+	if len(it.data) == 4 {
+		return Usage(it.unsigned())
+	}
+
+	return MakeUsage(g.usagePage, uint16(it.unsigned()))
+}
+
+func (g *globalState) logicalRange() (int64, int64) {
+	lo, hi := int64(g.logicalMin.signed()), int64(g.logicalMax.signed())
+
+	if lo >= 0 && hi < lo {
+		hi = int64(g.logicalMax.unsigned())
+	}
+
+	return lo, hi
+}
+
+func (s *localState) expand(f Field) []Usage {
+	usages := slices.Clone(s.usages)
+
+	if s.hasRange && s.usageMax >= s.usageMin {
+		n := int(s.usageMax-s.usageMin) + 1
+		if f.Flags&Variable != 0 {
+			n = min(n, f.Count)
+		}
+
+		for i := range n {
+			usages = append(usages, s.usageMin+Usage(i))
+		}
+	}
+
+	return usages
+}
+
+func mainKind(tag uint8) ReportKind {
+	switch tag {
+	case TagOutput:
+		return Output
+	case TagFeature:
+		return Feature
+	}
+
+	return Input
+}
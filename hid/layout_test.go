@@ -0,0 +1,302 @@
+package hid_test
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+)
+
+var (
+	axisX    = hid.MakeUsage(hid.PageGenericDesktop, 0x30)
+	axisY    = hid.MakeUsage(hid.PageGenericDesktop, 0x31)
+	axisZ    = hid.MakeUsage(hid.PageGenericDesktop, 0x32)
+	dial     = hid.MakeUsage(hid.PageGenericDesktop, 0x37)
+	volumeUp = hid.MakeUsage(hid.PageConsumer, 0xe9)
+	volumeDn = hid.MakeUsage(hid.PageConsumer, 0xea)
+	mute     = hid.MakeUsage(hid.PageConsumer, 0xe2)
+	playStop = hid.MakeUsage(hid.PageConsumer, 0xcd)
+)
+
+func testDescriptor() []byte {
+	return hid.NewBuilder().
+		UsagePage(hid.PageGenericDesktop).
+		Usage(0x04).
+		Collection(hid.CollectionApplication).
+		ReportID(1).
+		Push().
+		Usage(0x30).
+		Usage(0x31).
+		LogicalRange(-32768, 32767).
+		ReportSize(16).
+		ReportCount(2).
+		Input(hid.Variable).
+		Pop().
+		Usage(0x32).
+		LogicalRange(-2048, 2047).
+		ReportSize(12).
+		ReportCount(1).
+		Input(hid.Variable).
+		Padding(hid.Input, 4).
+		Usage(0x37).
+		LogicalRange(0, 255).
+		ReportSize(8).
+		ReportCount(1).
+		Feature(hid.Variable).
+		ReportID(2).
+		UsagePage(hid.PageConsumer).
+		Usage(0xe9).
+		Usage(0xea).
+		Usage(0xe2).
+		Usage(0xcd).
+		LogicalRange(1, 4).
+		ReportSize(4).
+		ReportCount(2).
+		Input(hid.Array).
+		EndCollection().
+		Bytes()
+}
+
+func TestParse(t *testing.T) {
+	l, err := hid.Parse(testDescriptor())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if !l.UsesIDs {
+		t.Fatal("UsesIDs = false, want true")
+	}
+
+	type spec struct {
+		kind     hid.ReportKind
+		id       uint8
+		offset   int
+		size     int
+		count    int
+		min, max int64
+		usages   int
+	}
+
+	want := []spec{
+		{hid.Input, 1, 0, 16, 2, -32768, 32767, 2},
+		{hid.Input, 1, 32, 12, 1, -2048, 2047, 1},
+		{hid.Input, 1, 44, 4, 1, -2048, 2047, 0},
+		{hid.Feature, 1, 0, 8, 1, 0, 255, 1},
+		{hid.Input, 2, 0, 4, 2, 1, 4, 4},
+	}
+
+	if len(l.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(l.Fields), len(want))
+	}
+
+	for i, w := range want {
+		f := l.Fields[i]
+		got := spec{f.Kind, f.ReportID, f.Offset, f.Size, f.Count, f.LogicalMin, f.LogicalMax, len(f.Usages)}
+
+		if got != w {
+			t.Errorf("field %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	lengths := []struct {
+		kind hid.ReportKind
+		id   uint8
+		want int
+	}{
+		{hid.Input, 1, 7},
+		{hid.Input, 2, 2},
+		{hid.Feature, 1, 2},
+		{hid.Output, 1, 0},
+		{hid.Input, 3, 0},
+	}
+
+	for _, tt := range lengths {
+		if n := l.ReportLength(tt.kind, tt.id); n != tt.want {
+			t.Errorf("ReportLength(%s, %d) = %d, want %d", tt.kind, tt.id, n, tt.want)
+		}
+	}
+
+	if ids := l.ReportIDs(hid.Input); !bytes.Equal(ids, []byte{1, 2}) {
+		t.Errorf("ReportIDs(input) = %v, want [1 2]", ids)
+	}
+}
+
+func TestPackUnpack(t *testing.T) {
+	l, err := hid.Parse(testDescriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		kind   hid.ReportKind
+		id     uint8
+		values map[hid.Usage]int32
+		report []byte
+	}{
+		{
+			name:   "signed axes",
+			kind:   hid.Input,
+			id:     1,
+			values: map[hid.Usage]int32{axisX: -32768, axisY: -2, axisZ: -2048},
+			report: []byte{0x01, 0x00, 0x80, 0xfe, 0xff, 0x00, 0x08},
+		},
+		{
+			name:   "positive axes",
+			kind:   hid.Input,
+			id:     1,
+			values: map[hid.Usage]int32{axisX: 32767, axisY: 1, axisZ: 2047},
+			report: []byte{0x01, 0xff, 0x7f, 0x01, 0x00, 0xff, 0x07},
+		},
+		{
+			name:   "feature",
+			kind:   hid.Feature,
+			id:     1,
+			values: map[hid.Usage]int32{dial: 200},
+			report: []byte{0x01, 0xc8},
+		},
+		{
+			name:   "array",
+			kind:   hid.Input,
+			id:     2,
+			values: map[hid.Usage]int32{volumeDn: 1, playStop: 1},
+			report: []byte{0x02, 0x42},
+		},
+		{
+			name:   "array single",
+			kind:   hid.Input,
+			id:     2,
+			values: map[hid.Usage]int32{mute: 1},
+			report: []byte{0x02, 0x03},
+		},
+		{
+			name:   "array empty",
+			kind:   hid.Input,
+			id:     2,
+			values: map[hid.Usage]int32{},
+			report: []byte{0x02, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := l.Pack(tt.kind, tt.id, tt.values)
+			if err != nil {
+				t.Fatalf("Pack: %v", err)
+			}
+
+			if !bytes.Equal(report, tt.report) {
+				t.Fatalf("Pack = % x, want % x", report, tt.report)
+			}
+
+			id, values, err := l.Unpack(tt.kind, report)
+			if err != nil {
+				t.Fatalf("Unpack: %v", err)
+			}
+
+			if id != tt.id {
+				t.Fatalf("Unpack id = %d, want %d", id, tt.id)
+			}
+
+			if !maps.Equal(values, tt.values) {
+				t.Fatalf("Unpack = %v, want %v", values, tt.values)
+			}
+		})
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	l, err := hid.Parse(testDescriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := l.NewReport(hid.Input, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []int32{-2048, -1, 0, 1, 2047} {
+		if err := l.Set(report, hid.Input, axisZ, v); err != nil {
+			t.Fatalf("Set(%d): %v", v, err)
+		}
+
+		if got, err := l.Get(report, hid.Input, axisZ); err != nil || got != v {
+			t.Fatalf("Get = %d, %v, want %d", got, err, v)
+		}
+
+		if got, _ := l.Get(report, hid.Input, axisY); got != 0 {
+			t.Fatalf("Set(Z) changed Y to %d", got)
+		}
+	}
+
+	if err := l.Set(report, hid.Input, axisZ, 2048); !errors.Is(err, hid.ErrValueRange) {
+		t.Fatalf("Set out of range = %v, want ErrValueRange", err)
+	}
+
+	if err := l.Set(report, hid.Input, dial, 1); !errors.Is(err, hid.ErrUnknownUsage) {
+		t.Fatalf("Set feature usage on input = %v, want ErrUnknownUsage", err)
+	}
+}
+
+func TestPackErrors(t *testing.T) {
+	l, err := hid.Parse(testDescriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Pack(hid.Input, 1, map[hid.Usage]int32{axisX: 40000}); !errors.Is(err, hid.ErrValueRange) {
+		t.Errorf("Pack out of range = %v, want ErrValueRange", err)
+	}
+
+	if _, err := l.Pack(hid.Input, 1, map[hid.Usage]int32{volumeUp: 1}); !errors.Is(err, hid.ErrUnknownUsage) {
+		t.Errorf("Pack usage from other report = %v, want ErrUnknownUsage", err)
+	}
+
+	if _, err := l.Pack(hid.Output, 1, nil); !errors.Is(err, hid.ErrUnknownReport) {
+		t.Errorf("Pack unknown report = %v, want ErrUnknownReport", err)
+	}
+
+	if _, _, err := l.Unpack(hid.Input, []byte{0x01, 0x00}); !errors.Is(err, hid.ErrShortReport) {
+		t.Errorf("Unpack short = %v, want ErrShortReport", err)
+	}
+
+	if _, _, err := l.Unpack(hid.Input, []byte{0x07, 0x00}); !errors.Is(err, hid.ErrUnknownReport) {
+		t.Errorf("Unpack unknown id = %v, want ErrUnknownReport", err)
+	}
+}
+
+func TestParseMaxReport(t *testing.T) {
+	l, err := hid.Parse(hid.NewBuilder().ReportSize(8).ReportCount(4096).Input(hid.Variable).Bytes())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if n := l.ReportLength(hid.Input, 0); n != 4096 {
+		t.Fatalf("ReportLength = %d, want 4096", n)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		desc []byte
+	}{
+		{name: "unbalanced end collection", desc: hid.NewBuilder().EndCollection().Bytes()},
+		{name: "unterminated collection", desc: hid.NewBuilder().Collection(hid.CollectionApplication).Bytes()},
+		{name: "pop without push", desc: hid.NewBuilder().Pop().Bytes()},
+		{name: "zero report id", desc: hid.NewBuilder().ReportID(0).Bytes()},
+		{name: "truncated item", desc: []byte{0x26, 0xff}},
+		{name: "huge report", desc: hid.NewBuilder().ReportSize(32).ReportCount(0xffffffff).Input(hid.Variable).Bytes()},
+		{name: "report over 4096 bytes", desc: hid.NewBuilder().ReportSize(8).ReportCount(4097).Input(hid.Variable).Bytes()},
+		{name: "report id pushes past 4096 bytes", desc: hid.NewBuilder().ReportID(1).ReportSize(8).ReportCount(4096).Input(hid.Variable).Bytes()},
+	}
+
+	for _, tt := range tests {
+		if _, err := hid.Parse(tt.desc); !errors.Is(err, hid.ErrInvalidDescriptor) {
+			t.Errorf("%s: Parse = %v, want ErrInvalidDescriptor", tt.name, err)
+		}
+	}
+}
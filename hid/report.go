@@ -0,0 +1,255 @@
+package hid
+
+import (
+	"fmt"
+	"slices"
+)
+
+func (l *Layout) NewReport(kind ReportKind, id uint8) ([]byte, error) {
+	n := l.ReportLength(kind, id)
+	if n == 0 {
+		return nil, fmt.Errorf("%w: %s %d", ErrUnknownReport, kind, id)
+	}
+
+	report := make([]byte, n)
+	if l.UsesIDs {
+		report[0] = id
+	}
+
+	return report, nil
+}
+
+func (l *Layout) Pack(kind ReportKind, id uint8, values map[Usage]int32) ([]byte, error) {
+	report, err := l.NewReport(kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	body := l.body(report)
+	used := make(map[Usage]bool, len(values))
+
+	for i := range l.Fields {
+		f := &l.Fields[i]
+		if f.Kind != kind || f.ReportID != id || f.IsConstant() {
+			continue
+		}
+
+		if f.IsVariable() {
+			for j := range f.Count {
+				u, ok := f.usageAt(j)
+				if !ok {
+					break
+				}
+
+				v, ok := values[u]
+				if !ok {
+					continue
+				}
+
+				if err := f.check(u, v); err != nil {
+					return nil, err
+				}
+
+				setBits(body, f.Offset+j*f.Size, f.Size, uint64(v))
+				used[u] = true
+			}
+
+			continue
+		}
+
+		slot := 0
+
+		for j, u := range f.Usages {
+			if values[u] == 0 || slot >= f.Count {
+				continue
+			}
+
+			setBits(body, f.Offset+slot*f.Size, f.Size, uint64(f.LogicalMin+int64(j)))
+			used[u] = true
+			slot++
+		}
+	}
+
+	var unknown []Usage
+
+	for u, v := range values {
+		if !used[u] && v != 0 {
+			unknown = append(unknown, u)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		return nil, fmt.Errorf("%w: %s %d: %v", ErrUnknownUsage, kind, id, unknown)
+	}
+
+	return report, nil
+}
+
+func (l *Layout) Unpack(kind ReportKind, report []byte) (uint8, map[Usage]int32, error) {
+	var id uint8
+
+	if l.UsesIDs {
+		if len(report) == 0 {
+			return 0, nil, ErrShortReport
+		}
+
+		id = report[0]
+	}
+
+	if n := l.ReportLength(kind, id); n == 0 {
+		return 0, nil, fmt.Errorf("%w: %s %d", ErrUnknownReport, kind, id)
+	} else if len(report) < n {
+		return 0, nil, fmt.Errorf("%w: %d < %d bytes", ErrShortReport, len(report), n)
+	}
+
+	body := l.body(report)
+	values := make(map[Usage]int32)
+
+	for i := range l.Fields {
+		f := &l.Fields[i]
+		if f.Kind != kind || f.ReportID != id || f.IsConstant() {
+			continue
+		}
+
+		for j := range f.Count {
+			v := f.value(getBits(body, f.Offset+j*f.Size, f.Size))
+
+			if f.IsVariable() {
+				if u, ok := f.usageAt(j); ok {
+					values[u] = int32(v)
+				}
+
+				continue
+			}
+
+			if idx := v - f.LogicalMin; v >= f.LogicalMin && v <= f.LogicalMax && idx < int64(len(f.Usages)) {
+				if u := f.Usages[idx]; u.ID() != 0 {
+					values[u] = 1
+				}
+			}
+		}
+	}
+
+	return id, values, nil
+}
+
+func (l *Layout) Set(report []byte, kind ReportKind, u Usage, v int32) error {
+	f, j, err := l.find(report, kind, u)
+	if err != nil {
+		return err
+	}
+
+	if err := f.check(u, v); err != nil {
+		return err
+	}
+
+	setBits(l.body(report), f.Offset+j*f.Size, f.Size, uint64(v))
+
+	return nil
+}
+
+func (l *Layout) Get(report []byte, kind ReportKind, u Usage) (int32, error) {
+	f, j, err := l.find(report, kind, u)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(f.value(getBits(l.body(report), f.Offset+j*f.Size, f.Size))), nil
+}
+
+func (l *Layout) find(report []byte, kind ReportKind, u Usage) (*Field, int, error) {
+	var id uint8
+
+	if l.UsesIDs {
+		if len(report) == 0 {
+			return nil, 0, ErrShortReport
+		}
+
+		id = report[0]
+	}
+
+	if n := l.ReportLength(kind, id); len(report) < n || n == 0 {
+		return nil, 0, fmt.Errorf("%w: %s %d", ErrShortReport, kind, id)
+	}
+
+	for i := range l.Fields {
+		f := &l.Fields[i]
+		if f.Kind != kind || f.ReportID != id || f.IsConstant() || !f.IsVariable() {
+			continue
+		}
+
+		for j := range f.Count {
+			if fu, ok := f.usageAt(j); ok && fu == u {
+				return f, j, nil
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("%w: %s", ErrUnknownUsage, u)
+}
+
+func (l *Layout) body(report []byte) []byte {
+	if l.UsesIDs {
+		return report[1:]
+	}
+
+	return report
+}
+
+func (f *Field) usageAt(i int) (Usage, bool) {
+	if len(f.Usages) == 0 {
+		return 0, false
+	}
+
+	return f.Usages[min(i, len(f.Usages)-1)], true
+}
+
+func (f *Field) check(u Usage, v int32) error {
+	if int64(v) < f.LogicalMin || int64(v) > f.LogicalMax {
+		return fmt.Errorf("%w: %s = %d not in [%d, %d]", ErrValueRange, u, v, f.LogicalMin, f.LogicalMax)
+	}
+
+	return nil
+}
+
+func (f *Field) value(raw uint64) int64 {
+	if f.LogicalMin < 0 && f.Size > 0 && f.Size < 64 && raw&(1<<(f.Size-1)) != 0 {
+		return int64(raw) - 1<<f.Size
+	}
+
+	return int64(raw)
+}
+
+func setBits(buf []byte, off, size int, v uint64) {
+	for i := range size {
+		pos := off + i
+		if pos/8 >= len(buf) {
+			return
+		}
+
+		if v>>i&1 != 0 {
+			buf[pos/8] |= 1 << (pos % 8)
+		} else {
+			buf[pos/8] &^= 1 << (pos % 8)
+		}
+	}
+}
+
+func getBits(buf []byte, off, size int) uint64 {
+	var v uint64
+
+	for i := range size {
+		pos := off + i
+		if pos/8 >= len(buf) {
+			break
+		}
+
+		if buf[pos/8]>>(pos%8)&1 != 0 {
+			v |= 1 << i
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,94 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+)
+
+type HIDDevice struct {
+	mutex    sync.Mutex
+	client   *Client
+	id       uint16
+	layout   *hid.Layout
+	onOutput func(reportID uint8, values map[hid.Usage]int32)
+	closed   bool
+}
+
+func (c *Client) NewHIDDevice(ctx context.Context, id, vendorID, productID uint16, name string, desc []byte) (*HIDDevice, error) {
+	layout, err := hid.Parse(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &HIDDevice{client: c, id: id, layout: layout}
+
+	if err := c.createUhid(ctx, id, vendorID, productID, name, desc, &uhidDevice{output: d.handleOutput, markClosed: d.markClosed}); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *HIDDevice) ID() uint16 { return d.id }
+
+func (d *HIDDevice) Layout() *hid.Layout { return d.layout }
+
+func (d *HIDDevice) Send(ctx context.Context, reportID uint8, values map[hid.Usage]int32) error {
+	report, err := d.layout.Pack(hid.Input, reportID, values)
+	if err != nil {
+		return err
+	}
+
+	return d.SendRaw(ctx, report)
+}
+
+func (d *HIDDevice) SendRaw(ctx context.Context, report []byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("%w: %d", ErrUhidClosed, d.id)
+	}
+
+	return d.client.UhidInputCtx(ctx, d.id, report)
+}
+
+func (d *HIDDevice) OnOutput(fn func(reportID uint8, values map[hid.Usage]int32)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.onOutput = fn
+}
+
+func (d *HIDDevice) Close() error {
+	d.markClosed()
+
+	return d.client.destroyUhid(d.id)
+}
+
+func (d *HIDDevice) markClosed() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.closed = true
+}
+
+func (d *HIDDevice) handleOutput(data []byte) {
+	d.mutex.Lock()
+	fn := d.onOutput
+	d.mutex.Unlock()
+
+	if fn == nil {
+		return
+	}
+
+	id, values, err := d.layout.Unpack(hid.Output, data)
+	if err != nil {
+		return
+	}
+
+	fn(id, values)
+}
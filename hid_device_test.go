@@ -0,0 +1,74 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+func TestHIDDevice(t *testing.T) {
+	c, next := pipeClient(t)
+	ctx := context.Background()
+
+	desc := hid.NewBuilder().
+		UsagePage(hid.PageGenericDesktop).
+		Usage(0x05).
+		Collection(hid.CollectionApplication).
+		Usage(0x30).
+		LogicalRange(-128, 127).
+		ReportSize(8).
+		ReportCount(1).
+		Input(hid.Variable).
+		UsagePage(hid.PageLED).
+		Usage(0x01).
+		LogicalRange(0, 1).
+		ReportSize(1).
+		ReportCount(1).
+		Output(hid.Variable).
+		Padding(hid.Output, 7).
+		EndCollection().
+		Bytes()
+
+	d, err := c.NewHIDDevice(ctx, 4, 0x1234, 0x5678, "custom", desc)
+	if err != nil {
+		t.Fatalf("NewHIDDevice: %v", err)
+	}
+
+	if m, ok := next(1)[0].(*protocol.UhidCreate); !ok || m.ID != 4 || m.VendorID != 0x1234 || !bytes.Equal(m.ReportDesc, desc) {
+		t.Fatalf("unexpected create message")
+	}
+
+	x := hid.MakeUsage(hid.PageGenericDesktop, 0x30)
+
+	if err := d.Send(ctx, 0, map[hid.Usage]int32{x: -2}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if report := uhidReports(t, next(1))[0]; !bytes.Equal(report, []byte{0xfe}) {
+		t.Fatalf("report = % x, want fe", report)
+	}
+
+	outputs := make(chan map[hid.Usage]int32, 1)
+	d.OnOutput(func(_ uint8, values map[hid.Usage]int32) { outputs <- values })
+
+	c.notifyUhidOutput(4, []byte{0x01})
+
+	if got, want := <-outputs, map[hid.Usage]int32{hid.MakeUsage(hid.PageLED, 1): 1}; !maps.Equal(got, want) {
+		t.Fatalf("output = %v, want %v", got, want)
+	}
+
+	if err := c.destroyAllUhid(); err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := d.SendRaw(ctx, []byte{0}); !errors.Is(err, ErrUhidClosed) {
+		t.Fatalf("SendRaw after client close = %v, want ErrUhidClosed", err)
+	}
+}
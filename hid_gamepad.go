@@ -0,0 +1,236 @@
+package scrcpy
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+const (
+	HIDGamepadFirstID = 3
+	HIDGamepadMaxID   = HIDGamepadFirstID + 7
+	hidGamepadName    = "scrcpy gamepad"
+	hidGamepadLen     = 15
+	hidTriggerMax     = 0x7fff
+)
+
+const (
+	HIDGamepadA          = 1 << 0
+	HIDGamepadB          = 1 << 1
+	HIDGamepadX          = 1 << 3
+	HIDGamepadY          = 1 << 4
+	HIDGamepadL1         = 1 << 6
+	HIDGamepadR1         = 1 << 7
+	HIDGamepadL2         = 1 << 8
+	HIDGamepadR2         = 1 << 9
+	HIDGamepadBack       = 1 << 10
+	HIDGamepadStart      = 1 << 11
+	HIDGamepadGuide      = 1 << 12
+	HIDGamepadLeftStick  = 1 << 13
+	HIDGamepadRightStick = 1 << 14
+)
+
+const (
+	HIDDPadUp    = 1 << 0
+	HIDDPadDown  = 1 << 1
+	HIDDPadLeft  = 1 << 2
+	HIDDPadRight = 1 << 3
+)
+
+var hidGamepadDesc = []byte{
+	0x05, 0x01, // Usage Page (Generic Desktop)
+	0x09, 0x05, // Usage (Gamepad)
+	0xa1, 0x01, // Collection (Application)
+	0xa1, 0x00, //   Collection (Physical)
+	0x05, 0x01, //     Usage Page (Generic Desktop)
+	0x09, 0x30, //     Usage (X)
+	0x09, 0x31, //     Usage (Y)
+	0x09, 0x32, //     Usage (Z)
+	0x09, 0x35, //     Usage (Rz)
+	0x15, 0x00, //     Logical Minimum (0)
+	0x27, 0xff, 0xff, 0x00, 0x00, // Logical Maximum (65535)
+	0x75, 0x10, //     Report Size (16)
+	0x95, 0x04, //     Report Count (4)
+	0x81, 0x02, //     Input (Data, Variable, Absolute)
+	0xc0,       //   End Collection
+	0x05, 0x02, //   Usage Page (Simulation Controls)
+	0x09, 0xc5, //   Usage (Brake)
+	0x09, 0xc4, //   Usage (Accelerator)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x26, 0xff, 0x7f, // Logical Maximum (32767)
+	0x75, 0x10, //   Report Size (16)
+	0x95, 0x02, //   Report Count (2)
+	0x81, 0x02, //   Input (Data, Variable, Absolute)
+	0x05, 0x09, //   Usage Page (Buttons)
+	0x19, 0x01, //   Usage Minimum (1)
+	0x29, 0x10, //   Usage Maximum (16)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x25, 0x01, //   Logical Maximum (1)
+	0x75, 0x01, //   Report Size (1)
+	0x95, 0x10, //   Report Count (16)
+	0x81, 0x02, //   Input (Data, Variable, Absolute)
+	0x05, 0x01, //   Usage Page (Generic Desktop)
+	0x09, 0x39, //   Usage (Hat Switch)
+	0x15, 0x01, //   Logical Minimum (1)
+	0x25, 0x08, //   Logical Maximum (8)
+	0x35, 0x00, //   Physical Minimum (0)
+	0x46, 0x3b, 0x01, // Physical Maximum (315)
+	0x65, 0x14, //   Unit (Degrees)
+	0x75, 0x04, //   Report Size (4)
+	0x95, 0x01, //   Report Count (1)
+	0x81, 0x42, //   Input (Data, Variable, Absolute, Null State)
+	0x75, 0x04, //   Report Size (4)
+	0x95, 0x01, //   Report Count (1)
+	0x81, 0x01, //   Input (Constant)
+	0xc0, // End Collection
+}
+
+type HIDGamepadState struct {
+	LeftX        int16
+	LeftY        int16
+	RightX       int16
+	RightY       int16
+	LeftTrigger  uint16
+	RightTrigger uint16
+	Buttons      uint16
+	DPad         uint8
+}
+
+type HIDGamepad struct {
+	mutex  sync.Mutex
+	client *Client
+	id     uint16
+	state  HIDGamepadState
+	closed bool
+}
+
+func (c *Client) NewHIDGamepad(ctx context.Context, id uint16) (*HIDGamepad, error) {
+	g := &HIDGamepad{client: c, id: id}
+
+	if err := c.createUhid(ctx, id, 0, 0, hidGamepadName, hidGamepadDesc, &uhidDevice{markClosed: g.markClosed}); err != nil {
+		return nil, err
+	}
+
+	if err := c.UhidInputCtx(ctx, id, g.state.report()); err != nil {
+		_ = c.destroyUhid(id)
+
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (g *HIDGamepad) ID() uint16 { return g.id }
+
+func (g *HIDGamepad) State() HIDGamepadState {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.state
+}
+
+func (g *HIDGamepad) Update(ctx context.Context, st HIDGamepadState) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.update(ctx, st)
+}
+
+func (g *HIDGamepad) update(ctx context.Context, st HIDGamepadState) error {
+	if g.closed {
+		return fmt.Errorf("%w: %d", ErrUhidClosed, g.id)
+	}
+
+	st.LeftTrigger = min(st.LeftTrigger, hidTriggerMax)
+	st.RightTrigger = min(st.RightTrigger, hidTriggerMax)
+
+	if st == g.state {
+		return nil
+	}
+
+	if err := g.client.UhidInputCtx(ctx, g.id, st.report()); err != nil {
+		return err
+	}
+
+	g.state = st
+
+	return nil
+}
+
+func (g *HIDGamepad) Button(ctx context.Context, btn uint16, down bool) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	st := g.state
+
+	if down {
+		st.Buttons |= btn
+	} else {
+		st.Buttons &^= btn
+	}
+
+	return g.update(ctx, st)
+}
+
+func (g *HIDGamepad) Reset(ctx context.Context) error {
+	return g.Update(ctx, HIDGamepadState{})
+}
+
+func (g *HIDGamepad) Close() error {
+	g.markClosed()
+
+	return g.client.destroyUhid(g.id)
+}
+
+func (g *HIDGamepad) markClosed() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.closed = true
+}
+
+func (s HIDGamepadState) report() []byte {
+	report := make([]byte, hidGamepadLen)
+
+	binary.LittleEndian.PutUint16(report[0:], axis(s.LeftX))
+	binary.LittleEndian.PutUint16(report[2:], axis(s.LeftY))
+	binary.LittleEndian.PutUint16(report[4:], axis(s.RightX))
+	binary.LittleEndian.PutUint16(report[6:], axis(s.RightY))
+	binary.LittleEndian.PutUint16(report[8:], s.LeftTrigger)
+	binary.LittleEndian.PutUint16(report[10:], s.RightTrigger)
+	binary.LittleEndian.PutUint16(report[12:], s.Buttons)
+	report[14] = hat(s.DPad)
+
+	return report
+}
+
+func axis(v int16) uint16 {
+	return uint16(int32(v) + 0x8000)
+}
+
+func hat(dpad uint8) uint8 {
+	up, down := dpad&HIDDPadUp != 0, dpad&HIDDPadDown != 0
+	left, right := dpad&HIDDPadLeft != 0, dpad&HIDDPadRight != 0
+
+	switch {
+	case up && right:
+		return 2
+	case down && right:
+		return 4
+	case down && left:
+		return 6
+	case up && left:
+		return 8
+	case up:
+		return 1
+	case right:
+		return 3
+	case down:
+		return 5
+	case left:
+		return 7
+	}
+
+	return 0
+}
@@ -0,0 +1,100 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+)
+
+func TestHIDGamepadDescriptor(t *testing.T) {
+	l := checkHIDLayout(t, hidGamepadDesc, []hidFieldSpec{
+		{hid.Input, hid.Variable, 0, 16, 4, 0, 0xffff, 4},
+		{hid.Input, hid.Variable, 64, 16, 2, 0, hidTriggerMax, 2},
+		{hid.Input, hid.Variable, 96, 1, 16, 0, 1, 16},
+		{hid.Input, hid.Variable | hid.NullState, 112, 4, 1, 1, 8, 1},
+		{hid.Input, hid.Constant, 116, 4, 1, 1, 8, 0},
+	}, hidGamepadLen, 0)
+
+	desktop := func(id uint16) hid.Usage { return hid.MakeUsage(hid.PageGenericDesktop, id) }
+	button := func(id uint16) hid.Usage { return hid.MakeUsage(hid.PageButton, id) }
+
+	st := HIDGamepadState{
+		LeftX:        -32768,
+		LeftY:        32767,
+		RightY:       -1,
+		LeftTrigger:  hidTriggerMax,
+		RightTrigger: 100,
+		Buttons:      HIDGamepadA | HIDGamepadStart,
+		DPad:         HIDDPadUp | HIDDPadRight,
+	}
+
+	values := map[hid.Usage]int32{
+		desktop(0x30):                           0,
+		desktop(0x31):                           0xffff,
+		desktop(0x32):                           0x8000,
+		desktop(0x35):                           0x7fff,
+		hid.MakeUsage(hid.PageSimulation, 0xc5): hidTriggerMax,
+		hid.MakeUsage(hid.PageSimulation, 0xc4): 100,
+		desktop(0x39):                           2,
+	}
+
+	for i := range uint16(16) {
+		values[button(i+1)] = int32(st.Buttons >> i & 1)
+	}
+
+	report, err := l.Pack(hid.Input, 0, values)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+
+	if !bytes.Equal(report, st.report()) {
+		t.Fatalf("Pack = % x, want % x", report, st.report())
+	}
+
+	_, got, err := l.Unpack(hid.Input, st.report())
+	if err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+
+	if !maps.Equal(got, values) {
+		t.Fatalf("Unpack = %v, want %v", got, values)
+	}
+}
+
+func TestHIDGamepadClientClose(t *testing.T) {
+	c, next := pipeClient(t)
+	ctx := context.Background()
+
+	g, err := c.NewHIDGamepad(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next(2)
+
+	if err := g.Button(ctx, HIDGamepadA, true); err != nil {
+		t.Fatalf("Button: %v", err)
+	}
+
+	if report := uhidReports(t, next(1))[0]; !bytes.Equal(report, g.State().report()) {
+		t.Fatalf("report = % x, want % x", report, g.State().report())
+	}
+
+	if err := c.destroyAllUhid(); err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := g.Reset(ctx); !errors.Is(err, ErrUhidClosed) {
+		t.Fatalf("Reset after client close = %v, want ErrUhidClosed", err)
+	}
+
+	if st := g.State(); st.Buttons != HIDGamepadA {
+		t.Fatalf("state changed by rejected update: %+v", st)
+	}
+}
@@ -0,0 +1,437 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	HIDKeyboardID   = 1
+	hidKeyboardName = "scrcpy keyboard"
+	hidMaxKeys      = 6
+	hidRollOver     = 0x01
+)
+
+const (
+	HIDModLeftCtrl   = 1 << 0
+	HIDModLeftShift  = 1 << 1
+	HIDModLeftAlt    = 1 << 2
+	HIDModLeftGUI    = 1 << 3
+	HIDModRightCtrl  = 1 << 4
+	HIDModRightShift = 1 << 5
+	HIDModRightAlt   = 1 << 6
+	HIDModRightGUI   = 1 << 7
+)
+
+const (
+	HIDLEDNumLock    = 1 << 0
+	HIDLEDCapsLock   = 1 << 1
+	HIDLEDScrollLock = 1 << 2
+	HIDLEDCompose    = 1 << 3
+	HIDLEDKana       = 1 << 4
+)
+
+const (
+	HIDKeyA            = 0x04
+	HIDKeyB            = 0x05
+	HIDKeyC            = 0x06
+	HIDKeyD            = 0x07
+	HIDKeyE            = 0x08
+	HIDKeyF            = 0x09
+	HIDKeyG            = 0x0a
+	HIDKeyH            = 0x0b
+	HIDKeyI            = 0x0c
+	HIDKeyJ            = 0x0d
+	HIDKeyK            = 0x0e
+	HIDKeyL            = 0x0f
+	HIDKeyM            = 0x10
+	HIDKeyN            = 0x11
+	HIDKeyO            = 0x12
+	HIDKeyP            = 0x13
+	HIDKeyQ            = 0x14
+	HIDKeyR            = 0x15
+	HIDKeyS            = 0x16
+	HIDKeyT            = 0x17
+	HIDKeyU            = 0x18
+	HIDKeyV            = 0x19
+	HIDKeyW            = 0x1a
+	HIDKeyX            = 0x1b
+	HIDKeyY            = 0x1c
+	HIDKeyZ            = 0x1d
+	HIDKey1            = 0x1e
+	HIDKey2            = 0x1f
+	HIDKey3            = 0x20
+	HIDKey4            = 0x21
+	HIDKey5            = 0x22
+	HIDKey6            = 0x23
+	HIDKey7            = 0x24
+	HIDKey8            = 0x25
+	HIDKey9            = 0x26
+	HIDKey0            = 0x27
+	HIDKeyEnter        = 0x28
+	HIDKeyEscape       = 0x29
+	HIDKeyBackspace    = 0x2a
+	HIDKeyTab          = 0x2b
+	HIDKeySpace        = 0x2c
+	HIDKeyMinus        = 0x2d
+	HIDKeyEqual        = 0x2e
+	HIDKeyLeftBracket  = 0x2f
+	HIDKeyRightBracket = 0x30
+	HIDKeyBackslash    = 0x31
+	HIDKeySemicolon    = 0x33
+	HIDKeyApostrophe   = 0x34
+	HIDKeyGrave        = 0x35
+	HIDKeyComma        = 0x36
+	HIDKeyPeriod       = 0x37
+	HIDKeySlash        = 0x38
+	HIDKeyCapsLock     = 0x39
+	HIDKeyF1           = 0x3a
+	HIDKeyF2           = 0x3b
+	HIDKeyF3           = 0x3c
+	HIDKeyF4           = 0x3d
+	HIDKeyF5           = 0x3e
+	HIDKeyF6           = 0x3f
+	HIDKeyF7           = 0x40
+	HIDKeyF8           = 0x41
+	HIDKeyF9           = 0x42
+	HIDKeyF10          = 0x43
+	HIDKeyF11          = 0x44
+	HIDKeyF12          = 0x45
+	HIDKeyPrintScreen  = 0x46
+	HIDKeyScrollLock   = 0x47
+	HIDKeyPause        = 0x48
+	HIDKeyInsert       = 0x49
+	HIDKeyHome         = 0x4a
+	HIDKeyPageUp       = 0x4b
+	HIDKeyDelete       = 0x4c
+	HIDKeyEnd          = 0x4d
+	HIDKeyPageDown     = 0x4e
+	HIDKeyRight        = 0x4f
+	HIDKeyLeft         = 0x50
+	HIDKeyDown         = 0x51
+	HIDKeyUp           = 0x52
+	HIDKeyNumLock      = 0x53
+	HIDKeyLeftCtrl     = 0xe0
+	HIDKeyLeftShift    = 0xe1
+	HIDKeyLeftAlt      = 0xe2
+	HIDKeyLeftGUI      = 0xe3
+	HIDKeyRightCtrl    = 0xe4
+	HIDKeyRightShift   = 0xe5
+	HIDKeyRightAlt     = 0xe6
+	HIDKeyRightGUI     = 0xe7
+)
+
+var hidKeyboardDesc = []byte{
+	0x05, 0x01, // Usage Page (Generic Desktop)
+	0x09, 0x06, // Usage (Keyboard)
+	0xa1, 0x01, // Collection (Application)
+	0x05, 0x07, //   Usage Page (Key Codes)
+	0x19, 0xe0, //   Usage Minimum (224)
+	0x29, 0xe7, //   Usage Maximum (231)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x25, 0x01, //   Logical Maximum (1)
+	0x75, 0x01, //   Report Size (1)
+	0x95, 0x08, //   Report Count (8)
+	0x81, 0x02, //   Input (Data, Variable, Absolute)
+	0x75, 0x08, //   Report Size (8)
+	0x95, 0x01, //   Report Count (1)
+	0x81, 0x01, //   Input (Constant)
+	0x05, 0x08, //   Usage Page (LEDs)
+	0x19, 0x01, //   Usage Minimum (1)
+	0x29, 0x05, //   Usage Maximum (5)
+	0x75, 0x01, //   Report Size (1)
+	0x95, 0x05, //   Report Count (5)
+	0x91, 0x02, //   Output (Data, Variable, Absolute)
+	0x75, 0x03, //   Report Size (3)
+	0x95, 0x01, //   Report Count (1)
+	0x91, 0x01, //   Output (Constant)
+	0x05, 0x07, //   Usage Page (Key Codes)
+	0x19, 0x00, //   Usage Minimum (0)
+	0x29, 0x65, //   Usage Maximum (101)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x25, 0x65, //   Logical Maximum (101)
+	0x75, 0x08, //   Report Size (8)
+	0x95, 0x06, //   Report Count (6)
+	0x81, 0x00, //   Input (Data, Array)
+	0xc0, // End Collection
+}
+
+type hidRuneKey struct {
+	usage uint8
+	shift bool
+}
+
+var hidRuneKeys = map[rune]hidRuneKey{
+	'\n': {HIDKeyEnter, false},
+	'\t': {HIDKeyTab, false},
+	' ':  {HIDKeySpace, false},
+	'-':  {HIDKeyMinus, false},
+	'=':  {HIDKeyEqual, false},
+	'[':  {HIDKeyLeftBracket, false},
+	']':  {HIDKeyRightBracket, false},
+	'\\': {HIDKeyBackslash, false},
+	';':  {HIDKeySemicolon, false},
+	'\'': {HIDKeyApostrophe, false},
+	'`':  {HIDKeyGrave, false},
+	',':  {HIDKeyComma, false},
+	'.':  {HIDKeyPeriod, false},
+	'/':  {HIDKeySlash, false},
+	'!':  {HIDKey1, true},
+	'@':  {HIDKey2, true},
+	'#':  {HIDKey3, true},
+	'$':  {HIDKey4, true},
+	'%':  {HIDKey5, true},
+	'^':  {HIDKey6, true},
+	'&':  {HIDKey7, true},
+	'*':  {HIDKey8, true},
+	'(':  {HIDKey9, true},
+	')':  {HIDKey0, true},
+	'_':  {HIDKeyMinus, true},
+	'+':  {HIDKeyEqual, true},
+	'{':  {HIDKeyLeftBracket, true},
+	'}':  {HIDKeyRightBracket, true},
+	'|':  {HIDKeyBackslash, true},
+	':':  {HIDKeySemicolon, true},
+	'"':  {HIDKeyApostrophe, true},
+	'~':  {HIDKeyGrave, true},
+	'<':  {HIDKeyComma, true},
+	'>':  {HIDKeyPeriod, true},
+	'?':  {HIDKeySlash, true},
+}
+
+func init() {
+	for r := 'a'; r <= 'z'; r++ {
+		hidRuneKeys[r] = hidRuneKey{usage: HIDKeyA + uint8(r-'a')}
+		hidRuneKeys[r-'a'+'A'] = hidRuneKey{usage: HIDKeyA + uint8(r-'a'), shift: true}
+	}
+
+	hidRuneKeys['0'] = hidRuneKey{usage: HIDKey0}
+
+	for r := '1'; r <= '9'; r++ {
+		hidRuneKeys[r] = hidRuneKey{usage: HIDKey1 + uint8(r-'1')}
+	}
+}
+
+type HIDKeyboard struct {
+	mutex  sync.Mutex
+	client *Client
+	id     uint16
+	mods   uint8
+	keys   []uint8
+	leds   uint8
+	onLEDs func(leds uint8)
+	closed bool
+}
+
+func (c *Client) NewHIDKeyboard(ctx context.Context, id uint16) (*HIDKeyboard, error) {
+	k := &HIDKeyboard{client: c, id: id}
+
+	if err := c.createUhid(ctx, id, 0, 0, hidKeyboardName, hidKeyboardDesc, &uhidDevice{output: k.handleOutput, markClosed: k.markClosed}); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func HIDUsageForRune(r rune) (usage, mods uint8, ok bool) {
+	k, ok := hidRuneKeys[r]
+	if !ok {
+		return 0, 0, false
+	}
+
+	if k.shift {
+		mods = HIDModLeftShift
+	}
+
+	return k.usage, mods, true
+}
+
+func (k *HIDKeyboard) ID() uint16 { return k.id }
+
+func (k *HIDKeyboard) KeyDown(ctx context.Context, usage uint8) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if bit, ok := hidModifierBit(usage); ok {
+		k.mods |= bit
+	} else if !slices.Contains(k.keys, usage) {
+		k.keys = append(k.keys, usage)
+	}
+
+	return k.send(ctx)
+}
+
+func (k *HIDKeyboard) KeyUp(ctx context.Context, usage uint8) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if bit, ok := hidModifierBit(usage); ok {
+		k.mods &^= bit
+	} else {
+		k.keys = slices.DeleteFunc(k.keys, func(u uint8) bool { return u == usage })
+	}
+
+	return k.send(ctx)
+}
+
+func (k *HIDKeyboard) SetModifiers(ctx context.Context, mods uint8) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.mods = mods
+
+	return k.send(ctx)
+}
+
+func (k *HIDKeyboard) Press(ctx context.Context, usage, mods uint8) (err error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	saved, savedKeys := k.mods, slices.Clone(k.keys)
+
+	defer func() {
+		if err != nil {
+			k.mods, k.keys = saved, savedKeys
+		}
+	}()
+
+	if mods&^saved != 0 {
+		k.mods |= mods
+
+		if err := k.send(ctx); err != nil {
+			return err
+		}
+	}
+
+	if !slices.Contains(k.keys, usage) {
+		k.keys = append(k.keys, usage)
+	}
+
+	if err := k.send(ctx); err != nil {
+		return err
+	}
+
+	k.keys = slices.DeleteFunc(k.keys, func(u uint8) bool { return u == usage })
+
+	if err := k.send(ctx); err != nil {
+		return err
+	}
+
+	if k.mods == saved {
+		return nil
+	}
+
+	k.mods = saved
+
+	return k.send(ctx)
+}
+
+func (k *HIDKeyboard) Type(ctx context.Context, text string) error {
+	for _, r := range text {
+		usage, mods, ok := HIDUsageForRune(r)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownKey, r)
+		}
+
+		if usage >= HIDKeyA && usage <= HIDKeyZ && k.CapsLock() {
+			mods ^= HIDModLeftShift
+		}
+
+		if err := k.Press(ctx, usage, mods); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *HIDKeyboard) ReleaseAll(ctx context.Context) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.mods = 0
+	k.keys = nil
+
+	return k.send(ctx)
+}
+
+func (k *HIDKeyboard) LEDs() uint8 {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.leds
+}
+
+func (k *HIDKeyboard) CapsLock() bool { return k.LEDs()&HIDLEDCapsLock != 0 }
+
+func (k *HIDKeyboard) NumLock() bool { return k.LEDs()&HIDLEDNumLock != 0 }
+
+func (k *HIDKeyboard) OnLEDs(fn func(leds uint8)) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.onLEDs = fn
+}
+
+func (k *HIDKeyboard) Close() error {
+	k.markClosed()
+
+	return k.client.destroyUhid(k.id)
+}
+
+func (k *HIDKeyboard) markClosed() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.closed = true
+}
+
+func (k *HIDKeyboard) report() []byte {
+	report := make([]byte, 2+hidMaxKeys)
+	report[0] = k.mods
+
+	if len(k.keys) > hidMaxKeys {
+		for i := range hidMaxKeys {
+			report[2+i] = hidRollOver
+		}
+
+		return report
+	}
+
+	copy(report[2:], k.keys)
+
+	return report
+}
+
+func (k *HIDKeyboard) send(ctx context.Context) error {
+	if k.closed {
+		return fmt.Errorf("%w: %d", ErrUhidClosed, k.id)
+	}
+
+	return k.client.UhidInputCtx(ctx, k.id, k.report())
+}
+
+func (k *HIDKeyboard) handleOutput(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	k.mutex.Lock()
+	k.leds = data[0]
+	fn := k.onLEDs
+	k.mutex.Unlock()
+
+	if fn != nil {
+		fn(data[0])
+	}
+}
+
+func hidModifierBit(usage uint8) (uint8, bool) {
+	if usage < HIDKeyLeftCtrl || usage > HIDKeyRightGUI {
+		return 0, false
+	}
+
+	return 1 << (usage - HIDKeyLeftCtrl), true
+}
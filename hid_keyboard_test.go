@@ -0,0 +1,290 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+type hidFieldSpec struct {
+	kind     hid.ReportKind
+	flags    hid.MainFlags
+	offset   int
+	size     int
+	count    int
+	min, max int64
+	usages   int
+}
+
+func checkHIDLayout(t *testing.T, desc []byte, fields []hidFieldSpec, inputLen, outputLen int) *hid.Layout {
+	t.Helper()
+
+	l, err := hid.Parse(desc)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if l.UsesIDs {
+		t.Fatal("UsesIDs = true, want false")
+	}
+
+	if len(l.Fields) != len(fields) {
+		t.Fatalf("got %d fields, want %d", len(l.Fields), len(fields))
+	}
+
+	for i, want := range fields {
+		f := l.Fields[i]
+		got := hidFieldSpec{f.Kind, f.Flags, f.Offset, f.Size, f.Count, f.LogicalMin, f.LogicalMax, len(f.Usages)}
+
+		if got != want {
+			t.Errorf("field %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if n := l.ReportLength(hid.Input, 0); n != inputLen {
+		t.Errorf("input ReportLength = %d, want %d", n, inputLen)
+	}
+
+	if n := l.ReportLength(hid.Output, 0); n != outputLen {
+		t.Errorf("output ReportLength = %d, want %d", n, outputLen)
+	}
+
+	return l
+}
+
+func TestHIDKeyboardDescriptor(t *testing.T) {
+	l := checkHIDLayout(t, hidKeyboardDesc, []hidFieldSpec{
+		{hid.Input, hid.Variable, 0, 1, 8, 0, 1, 8},
+		{hid.Input, hid.Constant, 8, 8, 1, 0, 1, 0},
+		{hid.Output, hid.Variable, 0, 1, 5, 0, 1, 5},
+		{hid.Output, hid.Constant, 5, 3, 1, 0, 1, 0},
+		{hid.Input, hid.Array, 16, 8, 6, 0, 101, 102},
+	}, 2+hidMaxKeys, 1)
+
+	key := func(id uint16) hid.Usage { return hid.MakeUsage(hid.PageKeyboard, id) }
+
+	k := &HIDKeyboard{mods: HIDModLeftShift | HIDModRightAlt, keys: []uint8{HIDKeyA, HIDKeyEnter, 0x65}}
+	values := map[hid.Usage]int32{
+		key(HIDKeyLeftShift): 1,
+		key(HIDKeyRightAlt):  1,
+		key(HIDKeyA):         1,
+		key(HIDKeyEnter):     1,
+		key(0x65):            1,
+	}
+
+	report, err := l.Pack(hid.Input, 0, values)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+
+	if !bytes.Equal(report, k.report()) {
+		t.Fatalf("Pack = % x, want % x", report, k.report())
+	}
+
+	_, got, err := l.Unpack(hid.Input, k.report())
+	if err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+
+	for u := key(HIDKeyLeftCtrl); u <= key(HIDKeyRightGUI); u++ {
+		if _, ok := values[u]; !ok {
+			values[u] = 0
+		}
+	}
+
+	if !maps.Equal(got, values) {
+		t.Fatalf("Unpack = %v, want %v", got, values)
+	}
+
+	k.keys = []uint8{1, 2, 3, 4, 5, 6, 7}
+
+	_, got, err = l.Unpack(hid.Input, k.report())
+	if err != nil {
+		t.Fatalf("Unpack rollover: %v", err)
+	}
+
+	if got[key(hidRollOver)] != 1 || got[key(HIDKeyA)] != 0 {
+		t.Fatalf("Unpack rollover = %v", got)
+	}
+
+	_, leds, err := l.Unpack(hid.Output, []byte{HIDLEDNumLock | HIDLEDCapsLock})
+	if err != nil {
+		t.Fatalf("Unpack output: %v", err)
+	}
+
+	if leds[hid.MakeUsage(hid.PageLED, 1)] != 1 || leds[hid.MakeUsage(hid.PageLED, 2)] != 1 || leds[hid.MakeUsage(hid.PageLED, 3)] != 0 {
+		t.Fatalf("Unpack output = %v", leds)
+	}
+}
+
+func pipeClient(t *testing.T) (*Client, func(n int) []protocol.ControlMessage) {
+	t.Helper()
+
+	host, device := net.Pipe()
+	c := &Client{opts: DefaultDialOptions(), controlConn: host}
+	c.writer = newControlWriter(host, c.opts.WriteTimeout, c.opts.WriteBatch)
+
+	msgs := make(chan protocol.ControlMessage, 256)
+
+	go func() {
+		defer close(msgs)
+
+		for {
+			msg, err := protocol.ReadControlMessage(device)
+			if err != nil {
+				return
+			}
+
+			msgs <- msg
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = c.Close()
+		_ = device.Close()
+	})
+
+	next := func(n int) []protocol.ControlMessage {
+		t.Helper()
+
+		got := make([]protocol.ControlMessage, 0, n)
+
+		for range n {
+			select {
+			case msg := <-msgs:
+				got = append(got, msg)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out after %d of %d messages", len(got), n)
+			}
+		}
+
+		return got
+	}
+
+	return c, next
+}
+
+func uhidReports(t *testing.T, msgs []protocol.ControlMessage) [][]byte {
+	t.Helper()
+
+	reports := make([][]byte, 0, len(msgs))
+
+	for _, msg := range msgs {
+		m, ok := msg.(*protocol.UhidInput)
+		if !ok {
+			t.Fatalf("unexpected message %v", msg)
+		}
+
+		reports = append(reports, m.Data)
+	}
+
+	return reports
+}
+
+func TestHIDKeyboardCapsLock(t *testing.T) {
+	c, next := pipeClient(t)
+	ctx := context.Background()
+
+	k, err := c.NewHIDKeyboard(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	c.notifyUhidOutput(1, []byte{HIDLEDCapsLock})
+
+	if !k.CapsLock() {
+		t.Fatal("CapsLock = false after LED output report")
+	}
+
+	if err := k.Type(ctx, "aB1"); err != nil {
+		t.Fatalf("Type: %v", err)
+	}
+
+	var pressed []string
+
+	for _, r := range uhidReports(t, next(8)) {
+		if r[2] != 0 {
+			pressed = append(pressed, fmt.Sprintf("%02x/%02x", r[0], r[2]))
+		}
+	}
+
+	want := []string{
+		fmt.Sprintf("%02x/%02x", HIDModLeftShift, HIDKeyA),
+		fmt.Sprintf("%02x/%02x", 0, HIDKeyB),
+		fmt.Sprintf("%02x/%02x", 0, HIDKey1),
+	}
+
+	if !slices.Equal(pressed, want) {
+		t.Fatalf("pressed = %v, want %v", pressed, want)
+	}
+}
+
+func TestHIDKeyboardPressRollback(t *testing.T) {
+	c, next := pipeClient(t)
+
+	k, err := c.NewHIDKeyboard(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := k.KeyDown(context.Background(), HIDKeyLeftCtrl); err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := k.Press(cancelled, HIDKeyA, HIDModLeftShift); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Press = %v, want context.Canceled", err)
+	}
+
+	k.mutex.Lock()
+	mods, keys := k.mods, slices.Clone(k.keys)
+	k.mutex.Unlock()
+
+	if mods != HIDModLeftCtrl || len(keys) != 0 {
+		t.Fatalf("state after failed Press = mods %#x keys %v, want ctrl and no keys", mods, keys)
+	}
+}
+
+func TestHIDKeyboardClientClose(t *testing.T) {
+	c, next := pipeClient(t)
+
+	k, err := c.NewHIDKeyboard(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := c.destroyAllUhid(); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, ok := next(1)[0].(*protocol.UhidDestroy); !ok || m.ID != 1 {
+		t.Fatalf("expected UHID_DESTROY for id 1")
+	}
+
+	if err := k.KeyDown(context.Background(), HIDKeyA); !errors.Is(err, ErrUhidClosed) {
+		t.Fatalf("KeyDown after client close = %v, want ErrUhidClosed", err)
+	}
+
+	if err := k.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	HIDMouseID   = 2
+	hidMouseName = "scrcpy mouse"
+)
+
+const (
+	HIDMouseLeft    = 1 << 0
+	HIDMouseRight   = 1 << 1
+	HIDMouseMiddle  = 1 << 2
+	HIDMouseBack    = 1 << 3
+	HIDMouseForward = 1 << 4
+)
+
+var hidMouseDesc = []byte{
+	0x05, 0x01, // Usage Page (Generic Desktop)
+	0x09, 0x02, // Usage (Mouse)
+	0xa1, 0x01, // Collection (Application)
+	0x09, 0x01, //   Usage (Pointer)
+	0xa1, 0x00, //   Collection (Physical)
+	0x05, 0x09, //     Usage Page (Buttons)
+	0x19, 0x01, //     Usage Minimum (1)
+	0x29, 0x05, //     Usage Maximum (5)
+	0x15, 0x00, //     Logical Minimum (0)
+	0x25, 0x01, //     Logical Maximum (1)
+	0x95, 0x05, //     Report Count (5)
+	0x75, 0x01, //     Report Size (1)
+	0x81, 0x02, //     Input (Data, Variable, Absolute)
+	0x95, 0x01, //     Report Count (1)
+	0x75, 0x03, //     Report Size (3)
+	0x81, 0x01, //     Input (Constant)
+	0x05, 0x01, //     Usage Page (Generic Desktop)
+	0x09, 0x30, //     Usage (X)
+	0x09, 0x31, //     Usage (Y)
+	0x09, 0x38, //     Usage (Wheel)
+	0x15, 0x81, //     Logical Minimum (-127)
+	0x25, 0x7f, //     Logical Maximum (127)
+	0x75, 0x08, //     Report Size (8)
+	0x95, 0x03, //     Report Count (3)
+	0x81, 0x06, //     Input (Data, Variable, Relative)
+	0x05, 0x0c, //     Usage Page (Consumer)
+	0x0a, 0x38, 0x02, // Usage (AC Pan)
+	0x15, 0x81, //     Logical Minimum (-127)
+	0x25, 0x7f, //     Logical Maximum (127)
+	0x75, 0x08, //     Report Size (8)
+	0x95, 0x01, //     Report Count (1)
+	0x81, 0x06, //     Input (Data, Variable, Relative)
+	0xc0, //   End Collection
+	0xc0, // End Collection
+}
+
+type HIDMouse struct {
+	mutex   sync.Mutex
+	client  *Client
+	id      uint16
+	buttons uint8
+	closed  bool
+}
+
+type MouseCapture struct {
+	mutex    sync.Mutex
+	mouse    *HIDMouse
+	scaleX   float64
+	scaleY   float64
+	captured bool
+	anchored bool
+	lastX    float64
+	lastY    float64
+	restX    float64
+	restY    float64
+}
+
+func (c *Client) NewHIDMouse(ctx context.Context, id uint16) (*HIDMouse, error) {
+	m := &HIDMouse{client: c, id: id}
+
+	if err := c.createUhid(ctx, id, 0, 0, hidMouseName, hidMouseDesc, &uhidDevice{markClosed: m.markClosed}); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *HIDMouse) ID() uint16 { return m.id }
+
+func (m *HIDMouse) Buttons() uint8 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.buttons
+}
+
+func (m *HIDMouse) Move(ctx context.Context, dx, dy int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for dx != 0 || dy != 0 {
+		x, y := clampInt8(dx), clampInt8(dy)
+
+		if err := m.send(ctx, x, y, 0, 0); err != nil {
+			return err
+		}
+
+		dx -= int(x)
+		dy -= int(y)
+	}
+
+	return nil
+}
+
+func (m *HIDMouse) Button(ctx context.Context, btn uint8, down bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if down {
+		m.buttons |= btn
+	} else {
+		m.buttons &^= btn
+	}
+
+	return m.send(ctx, 0, 0, 0, 0)
+}
+
+func (m *HIDMouse) SetButtons(ctx context.Context, buttons uint8) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if buttons == m.buttons {
+		return nil
+	}
+
+	m.buttons = buttons
+
+	return m.send(ctx, 0, 0, 0, 0)
+}
+
+func (m *HIDMouse) Click(ctx context.Context, btn uint8) error {
+	if err := m.Button(ctx, btn, true); err != nil {
+		return err
+	}
+
+	return m.Button(ctx, btn, false)
+}
+
+func (m *HIDMouse) Wheel(ctx context.Context, v, h int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for v != 0 || h != 0 {
+		y, x := clampInt8(v), clampInt8(h)
+
+		if err := m.send(ctx, 0, 0, y, x); err != nil {
+			return err
+		}
+
+		v -= int(y)
+		h -= int(x)
+	}
+
+	return nil
+}
+
+func (m *HIDMouse) Close() error {
+	m.markClosed()
+
+	return m.client.destroyUhid(m.id)
+}
+
+func (m *HIDMouse) markClosed() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.closed = true
+}
+
+func (m *HIDMouse) send(ctx context.Context, dx, dy, wheel, pan int8) error {
+	if m.closed {
+		return fmt.Errorf("%w: %d", ErrUhidClosed, m.id)
+	}
+
+	report := []byte{m.buttons, byte(dx), byte(dy), byte(wheel), byte(pan)}
+
+	return m.client.UhidInputCtx(ctx, m.id, report)
+}
+
+func NewMouseCapture(m *HIDMouse) *MouseCapture {
+	return &MouseCapture{mouse: m, scaleX: 1, scaleY: 1}
+}
+
+func (c *MouseCapture) Mouse() *HIDMouse { return c.mouse }
+
+func (c *MouseCapture) SetScale(x, y float64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.scaleX, c.scaleY = x, y
+}
+
+func (c *MouseCapture) Captured() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.captured
+}
+
+func (c *MouseCapture) Start() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.captured = true
+	c.anchored = false
+	c.restX, c.restY = 0, 0
+}
+
+func (c *MouseCapture) Stop(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.captured {
+		return nil
+	}
+
+	c.captured = false
+
+	return c.mouse.SetButtons(ctx, 0)
+}
+
+func (c *MouseCapture) Toggle(ctx context.Context) (bool, error) {
+	if c.Captured() {
+		return false, c.Stop(ctx)
+	}
+
+	c.Start()
+
+	return true, nil
+}
+
+func (c *MouseCapture) Motion(ctx context.Context, x, y float64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.captured {
+		return nil
+	}
+
+	if !c.anchored {
+		c.lastX, c.lastY = x, y
+		c.anchored = true
+
+		return nil
+	}
+
+	dx := (x-c.lastX)*c.scaleX + c.restX
+	dy := (y-c.lastY)*c.scaleY + c.restY
+	ix, iy := math.Trunc(dx), math.Trunc(dy)
+
+	c.lastX, c.lastY = x, y
+	c.restX, c.restY = dx-ix, dy-iy
+
+	if ix == 0 && iy == 0 {
+		return nil
+	}
+
+	return c.mouse.Move(ctx, int(ix), int(iy))
+}
+
+func (c *MouseCapture) Buttons(ctx context.Context, buttons uint8) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.captured {
+		return nil
+	}
+
+	return c.mouse.SetButtons(ctx, buttons)
+}
+
+func (c *MouseCapture) Wheel(ctx context.Context, v, h int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.captured {
+		return nil
+	}
+
+	return c.mouse.Wheel(ctx, v, h)
+}
+
+func clampInt8(v int) int8 {
+	return int8(max(min(v, 127), -127))
+}
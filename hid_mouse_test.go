@@ -0,0 +1,87 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/hid"
+)
+
+func TestHIDMouseDescriptor(t *testing.T) {
+	l := checkHIDLayout(t, hidMouseDesc, []hidFieldSpec{
+		{hid.Input, hid.Variable, 0, 1, 5, 0, 1, 5},
+		{hid.Input, hid.Constant, 5, 3, 1, 0, 1, 0},
+		{hid.Input, hid.Variable | hid.Relative, 8, 8, 3, -127, 127, 3},
+		{hid.Input, hid.Variable | hid.Relative, 32, 8, 1, -127, 127, 1},
+	}, 5, 0)
+
+	button := func(id uint16) hid.Usage { return hid.MakeUsage(hid.PageButton, id) }
+	desktop := func(id uint16) hid.Usage { return hid.MakeUsage(hid.PageGenericDesktop, id) }
+
+	values := map[hid.Usage]int32{
+		button(1):                              1,
+		button(2):                              0,
+		button(3):                              1,
+		button(4):                              0,
+		button(5):                              1,
+		desktop(0x30):                          -127,
+		desktop(0x31):                          5,
+		desktop(0x38):                          -1,
+		hid.MakeUsage(hid.PageConsumer, 0x238): 127,
+	}
+
+	dx, dy, wheel := int8(-127), int8(5), int8(-1)
+	want := []byte{HIDMouseLeft | HIDMouseMiddle | HIDMouseForward, byte(dx), byte(dy), byte(wheel), 0x7f}
+
+	report, err := l.Pack(hid.Input, 0, values)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+
+	if !bytes.Equal(report, want) {
+		t.Fatalf("Pack = % x, want % x", report, want)
+	}
+
+	_, got, err := l.Unpack(hid.Input, want)
+	if err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+
+	if !maps.Equal(got, values) {
+		t.Fatalf("Unpack = %v, want %v", got, values)
+	}
+}
+
+func TestHIDMouseClientClose(t *testing.T) {
+	c, next := pipeClient(t)
+	ctx := context.Background()
+
+	m, err := c.NewHIDMouse(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := m.Move(ctx, 300, -5); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+
+	reports := uhidReports(t, next(3))
+	if reports[0][1] != 127 || reports[1][1] != 127 || reports[2][1] != 46 || int8(reports[0][2]) != -5 || reports[1][2] != 0 {
+		t.Fatalf("Move reports = % x", reports)
+	}
+
+	if err := c.destroyAllUhid(); err != nil {
+		t.Fatal(err)
+	}
+
+	next(1)
+
+	if err := m.Click(ctx, HIDMouseLeft); !errors.Is(err, ErrUhidClosed) {
+		t.Fatalf("Click after client close = %v, want ErrUhidClosed", err)
+	}
+}
@@ -0,0 +1,379 @@
+package scrcpy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const keycodePrefix = "KEYCODE_"
+
+var keycodeNames = map[uint32]string{
+	AndroidKeyUnknown:                   "KEYCODE_UNKNOWN",
+	AndroidKeySoftLeft:                  "KEYCODE_SOFT_LEFT",
+	AndroidKeySoftRight:                 "KEYCODE_SOFT_RIGHT",
+	AndroidKeyHome:                      "KEYCODE_HOME",
+	AndroidKeyBack:                      "KEYCODE_BACK",
+	AndroidKeyCall:                      "KEYCODE_CALL",
+	AndroidKeyEndcall:                   "KEYCODE_ENDCALL",
+	AndroidKey0:                         "KEYCODE_0",
+	AndroidKey1:                         "KEYCODE_1",
+	AndroidKey2:                         "KEYCODE_2",
+	AndroidKey3:                         "KEYCODE_3",
+	AndroidKey4:                         "KEYCODE_4",
+	AndroidKey5:                         "KEYCODE_5",
+	AndroidKey6:                         "KEYCODE_6",
+	AndroidKey7:                         "KEYCODE_7",
+	AndroidKey8:                         "KEYCODE_8",
+	AndroidKey9:                         "KEYCODE_9",
+	AndroidKeyStar:                      "KEYCODE_STAR",
+	AndroidKeyPound:                     "KEYCODE_POUND",
+	AndroidKeyDpadUp:                    "KEYCODE_DPAD_UP",
+	AndroidKeyDpadDown:                  "KEYCODE_DPAD_DOWN",
+	AndroidKeyDpadLeft:                  "KEYCODE_DPAD_LEFT",
+	AndroidKeyDpadRight:                 "KEYCODE_DPAD_RIGHT",
+	AndroidKeyDpadCenter:                "KEYCODE_DPAD_CENTER",
+	AndroidKeyVolumeUp:                  "KEYCODE_VOLUME_UP",
+	AndroidKeyVolumeDown:                "KEYCODE_VOLUME_DOWN",
+	AndroidKeyPower:                     "KEYCODE_POWER",
+	AndroidKeyCamera:                    "KEYCODE_CAMERA",
+	AndroidKeyClear:                     "KEYCODE_CLEAR",
+	AndroidKeyA:                         "KEYCODE_A",
+	AndroidKeyB:                         "KEYCODE_B",
+	AndroidKeyC:                         "KEYCODE_C",
+	AndroidKeyD:                         "KEYCODE_D",
+	AndroidKeyE:                         "KEYCODE_E",
+	AndroidKeyF:                         "KEYCODE_F",
+	AndroidKeyG:                         "KEYCODE_G",
+	AndroidKeyH:                         "KEYCODE_H",
+	AndroidKeyI:                         "KEYCODE_I",
+	AndroidKeyJ:                         "KEYCODE_J",
+	AndroidKeyK:                         "KEYCODE_K",
+	AndroidKeyL:                         "KEYCODE_L",
+	AndroidKeyM:                         "KEYCODE_M",
+	AndroidKeyN:                         "KEYCODE_N",
+	AndroidKeyO:                         "KEYCODE_O",
+	AndroidKeyP:                         "KEYCODE_P",
+	AndroidKeyQ:                         "KEYCODE_Q",
+	AndroidKeyR:                         "KEYCODE_R",
+	AndroidKeyS:                         "KEYCODE_S",
+	AndroidKeyT:                         "KEYCODE_T",
+	AndroidKeyU:                         "KEYCODE_U",
+	AndroidKeyV:                         "KEYCODE_V",
+	AndroidKeyW:                         "KEYCODE_W",
+	AndroidKeyX:                         "KEYCODE_X",
+	AndroidKeyY:                         "KEYCODE_Y",
+	AndroidKeyZ:                         "KEYCODE_Z",
+	AndroidKeyComma:                     "KEYCODE_COMMA",
+	AndroidKeyPeriod:                    "KEYCODE_PERIOD",
+	AndroidKeyAltLeft:                   "KEYCODE_ALT_LEFT",
+	AndroidKeyAltRight:                  "KEYCODE_ALT_RIGHT",
+	AndroidKeyShiftLeft:                 "KEYCODE_SHIFT_LEFT",
+	AndroidKeyShiftRight:                "KEYCODE_SHIFT_RIGHT",
+	AndroidKeyTab:                       "KEYCODE_TAB",
+	AndroidKeySpace:                     "KEYCODE_SPACE",
+	AndroidKeySym:                       "KEYCODE_SYM",
+	AndroidKeyExplorer:                  "KEYCODE_EXPLORER",
+	AndroidKeyEnvelope:                  "KEYCODE_ENVELOPE",
+	AndroidKeyEnter:                     "KEYCODE_ENTER",
+	AndroidKeyDel:                       "KEYCODE_DEL",
+	AndroidKeyGrave:                     "KEYCODE_GRAVE",
+	AndroidKeyMinus:                     "KEYCODE_MINUS",
+	AndroidKeyEquals:                    "KEYCODE_EQUALS",
+	AndroidKeyLeftBracket:               "KEYCODE_LEFT_BRACKET",
+	AndroidKeyRightBracket:              "KEYCODE_RIGHT_BRACKET",
+	AndroidKeyBackslash:                 "KEYCODE_BACKSLASH",
+	AndroidKeySemicolon:                 "KEYCODE_SEMICOLON",
+	AndroidKeyApostrophe:                "KEYCODE_APOSTROPHE",
+	AndroidKeySlash:                     "KEYCODE_SLASH",
+	AndroidKeyAt:                        "KEYCODE_AT",
+	AndroidKeyNum:                       "KEYCODE_NUM",
+	AndroidKeyHeadsethook:               "KEYCODE_HEADSETHOOK",
+	AndroidKeyFocus:                     "KEYCODE_FOCUS",
+	AndroidKeyPlus:                      "KEYCODE_PLUS",
+	AndroidKeyMenu:                      "KEYCODE_MENU",
+	AndroidKeyNotification:              "KEYCODE_NOTIFICATION",
+	AndroidKeySearch:                    "KEYCODE_SEARCH",
+	AndroidKeyMediaPlayPause:            "KEYCODE_MEDIA_PLAY_PAUSE",
+	AndroidKeyMediaStop:                 "KEYCODE_MEDIA_STOP",
+	AndroidKeyMediaNext:                 "KEYCODE_MEDIA_NEXT",
+	AndroidKeyMediaPrevious:             "KEYCODE_MEDIA_PREVIOUS",
+	AndroidKeyMediaRewind:               "KEYCODE_MEDIA_REWIND",
+	AndroidKeyMediaFastForward:          "KEYCODE_MEDIA_FAST_FORWARD",
+	AndroidKeyMute:                      "KEYCODE_MUTE",
+	AndroidKeyPageUp:                    "KEYCODE_PAGE_UP",
+	AndroidKeyPageDown:                  "KEYCODE_PAGE_DOWN",
+	AndroidKeyPictsymbols:               "KEYCODE_PICTSYMBOLS",
+	AndroidKeySwitchCharset:             "KEYCODE_SWITCH_CHARSET",
+	AndroidKeyButtonA:                   "KEYCODE_BUTTON_A",
+	AndroidKeyButtonB:                   "KEYCODE_BUTTON_B",
+	AndroidKeyButtonC:                   "KEYCODE_BUTTON_C",
+	AndroidKeyButtonX:                   "KEYCODE_BUTTON_X",
+	AndroidKeyButtonY:                   "KEYCODE_BUTTON_Y",
+	AndroidKeyButtonZ:                   "KEYCODE_BUTTON_Z",
+	AndroidKeyButtonL1:                  "KEYCODE_BUTTON_L1",
+	AndroidKeyButtonR1:                  "KEYCODE_BUTTON_R1",
+	AndroidKeyButtonL2:                  "KEYCODE_BUTTON_L2",
+	AndroidKeyButtonR2:                  "KEYCODE_BUTTON_R2",
+	AndroidKeyButtonThumbl:              "KEYCODE_BUTTON_THUMBL",
+	AndroidKeyButtonThumbr:              "KEYCODE_BUTTON_THUMBR",
+	AndroidKeyButtonStart:               "KEYCODE_BUTTON_START",
+	AndroidKeyButtonSelect:              "KEYCODE_BUTTON_SELECT",
+	AndroidKeyButtonMode:                "KEYCODE_BUTTON_MODE",
+	AndroidKeyEscape:                    "KEYCODE_ESCAPE",
+	AndroidKeyForwardDel:                "KEYCODE_FORWARD_DEL",
+	AndroidKeyCtrlLeft:                  "KEYCODE_CTRL_LEFT",
+	AndroidKeyCtrlRight:                 "KEYCODE_CTRL_RIGHT",
+	AndroidKeyCapsLock:                  "KEYCODE_CAPS_LOCK",
+	AndroidKeyScrollLock:                "KEYCODE_SCROLL_LOCK",
+	AndroidKeyMetaLeft:                  "KEYCODE_META_LEFT",
+	AndroidKeyMetaRight:                 "KEYCODE_META_RIGHT",
+	AndroidKeyFunction:                  "KEYCODE_FUNCTION",
+	AndroidKeySysrq:                     "KEYCODE_SYSRQ",
+	AndroidKeyBreak:                     "KEYCODE_BREAK",
+	AndroidKeyMoveHome:                  "KEYCODE_MOVE_HOME",
+	AndroidKeyMoveEnd:                   "KEYCODE_MOVE_END",
+	AndroidKeyInsert:                    "KEYCODE_INSERT",
+	AndroidKeyForward:                   "KEYCODE_FORWARD",
+	AndroidKeyMediaPlay:                 "KEYCODE_MEDIA_PLAY",
+	AndroidKeyMediaPause:                "KEYCODE_MEDIA_PAUSE",
+	AndroidKeyMediaClose:                "KEYCODE_MEDIA_CLOSE",
+	AndroidKeyMediaEject:                "KEYCODE_MEDIA_EJECT",
+	AndroidKeyMediaRecord:               "KEYCODE_MEDIA_RECORD",
+	AndroidKeyF1:                        "KEYCODE_F1",
+	AndroidKeyF2:                        "KEYCODE_F2",
+	AndroidKeyF3:                        "KEYCODE_F3",
+	AndroidKeyF4:                        "KEYCODE_F4",
+	AndroidKeyF5:                        "KEYCODE_F5",
+	AndroidKeyF6:                        "KEYCODE_F6",
+	AndroidKeyF7:                        "KEYCODE_F7",
+	AndroidKeyF8:                        "KEYCODE_F8",
+	AndroidKeyF9:                        "KEYCODE_F9",
+	AndroidKeyF10:                       "KEYCODE_F10",
+	AndroidKeyF11:                       "KEYCODE_F11",
+	AndroidKeyF12:                       "KEYCODE_F12",
+	AndroidKeyNumLock:                   "KEYCODE_NUM_LOCK",
+	AndroidKeyNumpad0:                   "KEYCODE_NUMPAD_0",
+	AndroidKeyNumpad1:                   "KEYCODE_NUMPAD_1",
+	AndroidKeyNumpad2:                   "KEYCODE_NUMPAD_2",
+	AndroidKeyNumpad3:                   "KEYCODE_NUMPAD_3",
+	AndroidKeyNumpad4:                   "KEYCODE_NUMPAD_4",
+	AndroidKeyNumpad5:                   "KEYCODE_NUMPAD_5",
+	AndroidKeyNumpad6:                   "KEYCODE_NUMPAD_6",
+	AndroidKeyNumpad7:                   "KEYCODE_NUMPAD_7",
+	AndroidKeyNumpad8:                   "KEYCODE_NUMPAD_8",
+	AndroidKeyNumpad9:                   "KEYCODE_NUMPAD_9",
+	AndroidKeyNumpadDivide:              "KEYCODE_NUMPAD_DIVIDE",
+	AndroidKeyNumpadMultiply:            "KEYCODE_NUMPAD_MULTIPLY",
+	AndroidKeyNumpadSubtract:            "KEYCODE_NUMPAD_SUBTRACT",
+	AndroidKeyNumpadAdd:                 "KEYCODE_NUMPAD_ADD",
+	AndroidKeyNumpadDot:                 "KEYCODE_NUMPAD_DOT",
+	AndroidKeyNumpadComma:               "KEYCODE_NUMPAD_COMMA",
+	AndroidKeyNumpadEnter:               "KEYCODE_NUMPAD_ENTER",
+	AndroidKeyNumpadEquals:              "KEYCODE_NUMPAD_EQUALS",
+	AndroidKeyNumpadLeftParen:           "KEYCODE_NUMPAD_LEFT_PAREN",
+	AndroidKeyNumpadRightParen:          "KEYCODE_NUMPAD_RIGHT_PAREN",
+	AndroidKeyVolumeMute:                "KEYCODE_VOLUME_MUTE",
+	AndroidKeyInfo:                      "KEYCODE_INFO",
+	AndroidKeyChannelUp:                 "KEYCODE_CHANNEL_UP",
+	AndroidKeyChannelDown:               "KEYCODE_CHANNEL_DOWN",
+	AndroidKeyZoomIn:                    "KEYCODE_ZOOM_IN",
+	AndroidKeyZoomOut:                   "KEYCODE_ZOOM_OUT",
+	AndroidKeyTv:                        "KEYCODE_TV",
+	AndroidKeyWindow:                    "KEYCODE_WINDOW",
+	AndroidKeyGuide:                     "KEYCODE_GUIDE",
+	AndroidKeyDvr:                       "KEYCODE_DVR",
+	AndroidKeyBookmark:                  "KEYCODE_BOOKMARK",
+	AndroidKeyCaptions:                  "KEYCODE_CAPTIONS",
+	AndroidKeySettings:                  "KEYCODE_SETTINGS",
+	AndroidKeyTvPower:                   "KEYCODE_TV_POWER",
+	AndroidKeyTvInput:                   "KEYCODE_TV_INPUT",
+	AndroidKeyStbPower:                  "KEYCODE_STB_POWER",
+	AndroidKeyStbInput:                  "KEYCODE_STB_INPUT",
+	AndroidKeyAppSwitch:                 "KEYCODE_APP_SWITCH",
+	AndroidKeyButton1:                   "KEYCODE_BUTTON_1",
+	AndroidKeyButton2:                   "KEYCODE_BUTTON_2",
+	AndroidKeyButton3:                   "KEYCODE_BUTTON_3",
+	AndroidKeyButton4:                   "KEYCODE_BUTTON_4",
+	AndroidKeyButton5:                   "KEYCODE_BUTTON_5",
+	AndroidKeyButton6:                   "KEYCODE_BUTTON_6",
+	AndroidKeyButton7:                   "KEYCODE_BUTTON_7",
+	AndroidKeyButton8:                   "KEYCODE_BUTTON_8",
+	AndroidKeyButton9:                   "KEYCODE_BUTTON_9",
+	AndroidKeyButton10:                  "KEYCODE_BUTTON_10",
+	AndroidKeyButton11:                  "KEYCODE_BUTTON_11",
+	AndroidKeyButton12:                  "KEYCODE_BUTTON_12",
+	AndroidKeyButton13:                  "KEYCODE_BUTTON_13",
+	AndroidKeyButton14:                  "KEYCODE_BUTTON_14",
+	AndroidKeyButton15:                  "KEYCODE_BUTTON_15",
+	AndroidKeyButton16:                  "KEYCODE_BUTTON_16",
+	AndroidKeyLanguageSwitch:            "KEYCODE_LANGUAGE_SWITCH",
+	AndroidKeyMannerMode:                "KEYCODE_MANNER_MODE",
+	AndroidKey3dMode:                    "KEYCODE_3D_MODE",
+	AndroidKeyContacts:                  "KEYCODE_CONTACTS",
+	AndroidKeyCalendar:                  "KEYCODE_CALENDAR",
+	AndroidKeyMusic:                     "KEYCODE_MUSIC",
+	AndroidKeyCalculator:                "KEYCODE_CALCULATOR",
+	AndroidKeyZenkakuHankaku:            "KEYCODE_ZENKAKU_HANKAKU",
+	AndroidKeyEisu:                      "KEYCODE_EISU",
+	AndroidKeyMuhenkan:                  "KEYCODE_MUHENKAN",
+	AndroidKeyHenkan:                    "KEYCODE_HENKAN",
+	AndroidKeyKatakanaHiragana:          "KEYCODE_KATAKANA_HIRAGANA",
+	AndroidKeyYen:                       "KEYCODE_YEN",
+	AndroidKeyRo:                        "KEYCODE_RO",
+	AndroidKeyKana:                      "KEYCODE_KANA",
+	AndroidKeyAssist:                    "KEYCODE_ASSIST",
+	AndroidKeyBrightnessDown:            "KEYCODE_BRIGHTNESS_DOWN",
+	AndroidKeyBrightnessUp:              "KEYCODE_BRIGHTNESS_UP",
+	AndroidKeyMediaAudioTrack:           "KEYCODE_MEDIA_AUDIO_TRACK",
+	AndroidKeySleep:                     "KEYCODE_SLEEP",
+	AndroidKeyWakeup:                    "KEYCODE_WAKEUP",
+	AndroidKeyPairing:                   "KEYCODE_PAIRING",
+	AndroidKeyMediaTopMenu:              "KEYCODE_MEDIA_TOP_MENU",
+	AndroidKey11:                        "KEYCODE_11",
+	AndroidKey12:                        "KEYCODE_12",
+	AndroidKeyLastChannel:               "KEYCODE_LAST_CHANNEL",
+	AndroidKeyTvDataService:             "KEYCODE_TV_DATA_SERVICE",
+	AndroidKeyVoiceAssist:               "KEYCODE_VOICE_ASSIST",
+	AndroidKeyTvTeletext:                "KEYCODE_TV_TELETEXT",
+	AndroidKeyTvNumberEntry:             "KEYCODE_TV_NUMBER_ENTRY",
+	AndroidKeyTvTerrestrialAnalog:       "KEYCODE_TV_TERRESTRIAL_ANALOG",
+	AndroidKeyTvTerrestrialDigital:      "KEYCODE_TV_TERRESTRIAL_DIGITAL",
+	AndroidKeyTvSatellite:               "KEYCODE_TV_SATELLITE",
+	AndroidKeyTvSatelliteBs:             "KEYCODE_TV_SATELLITE_BS",
+	AndroidKeyTvSatelliteCs:             "KEYCODE_TV_SATELLITE_CS",
+	AndroidKeyTvSatelliteService:        "KEYCODE_TV_SATELLITE_SERVICE",
+	AndroidKeyTvNetwork:                 "KEYCODE_TV_NETWORK",
+	AndroidKeyTvAntennaCable:            "KEYCODE_TV_ANTENNA_CABLE",
+	AndroidKeyTvInputHdmi1:              "KEYCODE_TV_INPUT_HDMI_1",
+	AndroidKeyTvInputHdmi2:              "KEYCODE_TV_INPUT_HDMI_2",
+	AndroidKeyTvInputHdmi3:              "KEYCODE_TV_INPUT_HDMI_3",
+	AndroidKeyTvInputHdmi4:              "KEYCODE_TV_INPUT_HDMI_4",
+	AndroidKeyTvInputComposite1:         "KEYCODE_TV_INPUT_COMPOSITE_1",
+	AndroidKeyTvInputComposite2:         "KEYCODE_TV_INPUT_COMPOSITE_2",
+	AndroidKeyTvInputComponent1:         "KEYCODE_TV_INPUT_COMPONENT_1",
+	AndroidKeyTvInputComponent2:         "KEYCODE_TV_INPUT_COMPONENT_2",
+	AndroidKeyTvInputVga1:               "KEYCODE_TV_INPUT_VGA_1",
+	AndroidKeyTvAudioDescriptionMixUp:   "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_UP",
+	AndroidKeyTvAudioDescriptionMixDown: "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_DOWN",
+	AndroidKeyTvZoomMode:                "KEYCODE_TV_ZOOM_MODE",
+	AndroidKeyTvContentsMenu:            "KEYCODE_TV_CONTENTS_MENU",
+	AndroidKeyTvMediaContextMenu:        "KEYCODE_TV_MEDIA_CONTEXT_MENU",
+	AndroidKeyTvTimerProgramming:        "KEYCODE_TV_TIMER_PROGRAMMING",
+	AndroidKeyHelp:                      "KEYCODE_HELP",
+	AndroidKeyNavigatePrevious:          "KEYCODE_NAVIGATE_PREVIOUS",
+	AndroidKeyNavigateNext:              "KEYCODE_NAVIGATE_NEXT",
+	AndroidKeyNavigateIn:                "KEYCODE_NAVIGATE_IN",
+	AndroidKeyNavigateOut:               "KEYCODE_NAVIGATE_OUT",
+	AndroidKeyStem1:                     "KEYCODE_STEM_1",
+	AndroidKeyStem2:                     "KEYCODE_STEM_2",
+	AndroidKeyStem3:                     "KEYCODE_STEM_3",
+	AndroidKeyDpadUpLeft:                "KEYCODE_DPAD_UP_LEFT",
+	AndroidKeyDpadDownLeft:              "KEYCODE_DPAD_DOWN_LEFT",
+	AndroidKeyDpadUpRight:               "KEYCODE_DPAD_UP_RIGHT",
+	AndroidKeyDpadDownRight:             "KEYCODE_DPAD_DOWN_RIGHT",
+	AndroidKeyMediaSkipForward:          "KEYCODE_MEDIA_SKIP_FORWARD",
+	AndroidKeyMediaSkipBackward:         "KEYCODE_MEDIA_SKIP_BACKWARD",
+	AndroidKeyMediaStepForward:          "KEYCODE_MEDIA_STEP_FORWARD",
+	AndroidKeyMediaStepBackward:         "KEYCODE_MEDIA_STEP_BACKWARD",
+	AndroidKeySoftSleep:                 "KEYCODE_SOFT_SLEEP",
+	AndroidKeyCut:                       "KEYCODE_CUT",
+	AndroidKeyCopy:                      "KEYCODE_COPY",
+	AndroidKeyPaste:                     "KEYCODE_PASTE",
+	AndroidKeySystemNavigationUp:        "KEYCODE_SYSTEM_NAVIGATION_UP",
+	AndroidKeySystemNavigationDown:      "KEYCODE_SYSTEM_NAVIGATION_DOWN",
+	AndroidKeySystemNavigationLeft:      "KEYCODE_SYSTEM_NAVIGATION_LEFT",
+	AndroidKeySystemNavigationRight:     "KEYCODE_SYSTEM_NAVIGATION_RIGHT",
+	AndroidKeyAllApps:                   "KEYCODE_ALL_APPS",
+	AndroidKeyRefresh:                   "KEYCODE_REFRESH",
+	AndroidKeyThumbsUp:                  "KEYCODE_THUMBS_UP",
+	AndroidKeyThumbsDown:                "KEYCODE_THUMBS_DOWN",
+	AndroidKeyProfileSwitch:             "KEYCODE_PROFILE_SWITCH",
+	AndroidKeyVideoApp1:                 "KEYCODE_VIDEO_APP_1",
+	AndroidKeyVideoApp2:                 "KEYCODE_VIDEO_APP_2",
+	AndroidKeyVideoApp3:                 "KEYCODE_VIDEO_APP_3",
+	AndroidKeyVideoApp4:                 "KEYCODE_VIDEO_APP_4",
+	AndroidKeyVideoApp5:                 "KEYCODE_VIDEO_APP_5",
+	AndroidKeyVideoApp6:                 "KEYCODE_VIDEO_APP_6",
+	AndroidKeyVideoApp7:                 "KEYCODE_VIDEO_APP_7",
+	AndroidKeyVideoApp8:                 "KEYCODE_VIDEO_APP_8",
+	AndroidKeyFeaturedApp1:              "KEYCODE_FEATURED_APP_1",
+	AndroidKeyFeaturedApp2:              "KEYCODE_FEATURED_APP_2",
+	AndroidKeyFeaturedApp3:              "KEYCODE_FEATURED_APP_3",
+	AndroidKeyFeaturedApp4:              "KEYCODE_FEATURED_APP_4",
+	AndroidKeyDemoApp1:                  "KEYCODE_DEMO_APP_1",
+	AndroidKeyDemoApp2:                  "KEYCODE_DEMO_APP_2",
+	AndroidKeyDemoApp3:                  "KEYCODE_DEMO_APP_3",
+	AndroidKeyDemoApp4:                  "KEYCODE_DEMO_APP_4",
+	AndroidKeyKeyboardBacklightDown:     "KEYCODE_KEYBOARD_BACKLIGHT_DOWN",
+	AndroidKeyKeyboardBacklightUp:       "KEYCODE_KEYBOARD_BACKLIGHT_UP",
+	AndroidKeyStylusButtonPrimary:       "KEYCODE_STYLUS_BUTTON_PRIMARY",
+	AndroidKeyStylusButtonSecondary:     "KEYCODE_STYLUS_BUTTON_SECONDARY",
+	AndroidKeyStylusButtonTertiary:      "KEYCODE_STYLUS_BUTTON_TERTIARY",
+	AndroidKeyStylusButtonTail:          "KEYCODE_STYLUS_BUTTON_TAIL",
+	AndroidKeyRecentApps:                "KEYCODE_RECENT_APPS",
+	AndroidKeyMacro1:                    "KEYCODE_MACRO_1",
+	AndroidKeyMacro2:                    "KEYCODE_MACRO_2",
+	AndroidKeyMacro3:                    "KEYCODE_MACRO_3",
+	AndroidKeyMacro4:                    "KEYCODE_MACRO_4",
+	AndroidKeyEmojiPicker:               "KEYCODE_EMOJI_PICKER",
+	AndroidKeyScreenshot:                "KEYCODE_SCREENSHOT",
+	AndroidKeyDictate:                   "KEYCODE_DICTATE",
+	AndroidKeyNew:                       "KEYCODE_NEW",
+	AndroidKeyClose:                     "KEYCODE_CLOSE",
+	AndroidKeyDoNotDisturb:              "KEYCODE_DO_NOT_DISTURB",
+	AndroidKeyPrint:                     "KEYCODE_PRINT",
+	AndroidKeyLock:                      "KEYCODE_LOCK",
+	AndroidKeyFullscreen:                "KEYCODE_FULLSCREEN",
+	AndroidKeyF13:                       "KEYCODE_F13",
+	AndroidKeyF14:                       "KEYCODE_F14",
+	AndroidKeyF15:                       "KEYCODE_F15",
+	AndroidKeyF16:                       "KEYCODE_F16",
+	AndroidKeyF17:                       "KEYCODE_F17",
+	AndroidKeyF18:                       "KEYCODE_F18",
+	AndroidKeyF19:                       "KEYCODE_F19",
+	AndroidKeyF20:                       "KEYCODE_F20",
+	AndroidKeyF21:                       "KEYCODE_F21",
+	AndroidKeyF22:                       "KEYCODE_F22",
+	AndroidKeyF23:                       "KEYCODE_F23",
+	AndroidKeyF24:                       "KEYCODE_F24",
+}
+
+var keycodeValues = func() map[string]uint32 {
+	m := make(map[string]uint32, len(keycodeNames))
+	for code, name := range keycodeNames {
+		m[name] = code
+	}
+
+	return m
+}()
+
+func KeycodeName(code uint32) string {
+	if name, ok := keycodeNames[code]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("KEYCODE_%d", code)
+}
+
+func ParseKeycode(name string) (uint32, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, keycodePrefix) {
+		name = keycodePrefix + name
+	}
+
+	code, ok := keycodeValues[name]
+
+	return code, ok
+}
+
+func Keycodes() []uint32 {
+	codes := make([]uint32, 0, len(keycodeNames))
+	for code := range keycodeNames {
+		codes = append(codes, code)
+	}
+
+	slices.Sort(codes)
+
+	return codes
+}
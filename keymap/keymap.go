@@ -0,0 +1,238 @@
+package keymap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+)
+
+type Modifier uint8
+
+const (
+	ModShift Modifier = 1 << iota
+	ModCtrl
+	ModAlt
+	ModMeta
+)
+
+type Key uint16
+
+const (
+	KeyRune Key = iota
+	KeyEnter
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+var ErrUnmapped = errors.New("no android keycode for key")
+
+var namedKeys = map[Key]uint32{
+	KeyEnter:     scrcpy.AndroidKeyEnter,
+	KeyBackspace: scrcpy.AndroidKeyDel,
+	KeyDelete:    scrcpy.AndroidKeyForwardDel,
+	KeyTab:       scrcpy.AndroidKeyTab,
+	KeyEscape:    scrcpy.AndroidKeyEscape,
+	KeyUp:        scrcpy.AndroidKeyDpadUp,
+	KeyDown:      scrcpy.AndroidKeyDpadDown,
+	KeyLeft:      scrcpy.AndroidKeyDpadLeft,
+	KeyRight:     scrcpy.AndroidKeyDpadRight,
+	KeyHome:      scrcpy.AndroidKeyMoveHome,
+	KeyEnd:       scrcpy.AndroidKeyMoveEnd,
+	KeyPageUp:    scrcpy.AndroidKeyPageUp,
+	KeyPageDown:  scrcpy.AndroidKeyPageDown,
+	KeyInsert:    scrcpy.AndroidKeyInsert,
+	KeyF1:        scrcpy.AndroidKeyF1,
+	KeyF2:        scrcpy.AndroidKeyF2,
+	KeyF3:        scrcpy.AndroidKeyF3,
+	KeyF4:        scrcpy.AndroidKeyF4,
+	KeyF5:        scrcpy.AndroidKeyF5,
+	KeyF6:        scrcpy.AndroidKeyF6,
+	KeyF7:        scrcpy.AndroidKeyF7,
+	KeyF8:        scrcpy.AndroidKeyF8,
+	KeyF9:        scrcpy.AndroidKeyF9,
+	KeyF10:       scrcpy.AndroidKeyF10,
+	KeyF11:       scrcpy.AndroidKeyF11,
+	KeyF12:       scrcpy.AndroidKeyF12,
+}
+
+type Event struct {
+	Key  Key
+	Rune rune
+	Mods Modifier
+}
+
+type Stroke struct {
+	Keycode uint32
+	Meta    uint32
+}
+
+type Layout interface {
+	Lookup(r rune) (Stroke, bool)
+}
+
+type LayoutMap map[rune]Stroke
+
+func (m LayoutMap) Lookup(r rune) (Stroke, bool) {
+	s, ok := m[r]
+
+	return s, ok
+}
+
+type LayoutFunc func(r rune) (Stroke, bool)
+
+func (f LayoutFunc) Lookup(r rune) (Stroke, bool) { return f(r) }
+
+var US Layout = LayoutFunc(func(r rune) (Stroke, bool) {
+	code, meta, ok := scrcpy.RuneKeycode(r)
+
+	return Stroke{Keycode: code, Meta: meta}, ok
+})
+
+func Chain(layouts ...Layout) Layout {
+	return LayoutFunc(func(r rune) (Stroke, bool) {
+		for _, l := range layouts {
+			if s, ok := l.Lookup(r); ok {
+				return s, true
+			}
+		}
+
+		return Stroke{}, false
+	})
+}
+
+type Keyboard interface {
+	InjectKeycodeCtx(ctx context.Context, keycode uint32, action byte, repeat, meta uint32) error
+}
+
+type Translator struct {
+	mutex  sync.Mutex
+	layout Layout
+	held   map[uint32]uint32
+}
+
+func New(layout Layout) *Translator {
+	if layout == nil {
+		layout = US
+	}
+
+	return &Translator{
+		layout: layout,
+		held:   make(map[uint32]uint32),
+	}
+}
+
+func (t *Translator) Translate(ev Event) (Stroke, error) {
+	var s Stroke
+
+	if ev.Key == KeyRune {
+		var ok bool
+
+		if s, ok = t.layout.Lookup(ev.Rune); !ok {
+			return Stroke{}, fmt.Errorf("%w: %q", ErrUnmapped, ev.Rune)
+		}
+	} else {
+		code, ok := namedKeys[ev.Key]
+		if !ok {
+			return Stroke{}, fmt.Errorf("%w: key %d", ErrUnmapped, ev.Key)
+		}
+
+		s.Keycode = code
+	}
+
+	s.Meta |= MetaState(ev.Mods)
+
+	return s, nil
+}
+
+func (t *Translator) Down(ctx context.Context, kb Keyboard, ev Event) error {
+	s, err := t.Translate(ev)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	repeat, held := t.held[s.Keycode]
+	if held {
+		repeat++
+	}
+
+	if err := kb.InjectKeycodeCtx(ctx, s.Keycode, scrcpy.ActionDown, repeat, s.Meta); err != nil {
+		return err
+	}
+
+	t.held[s.Keycode] = repeat
+
+	return nil
+}
+
+func (t *Translator) Up(ctx context.Context, kb Keyboard, ev Event) error {
+	s, err := t.Translate(ev)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.held, s.Keycode)
+
+	return kb.InjectKeycodeCtx(ctx, s.Keycode, scrcpy.ActionUp, 0, s.Meta)
+}
+
+func (t *Translator) Press(ctx context.Context, kb Keyboard, ev Event) error {
+	if err := t.Down(ctx, kb, ev); err != nil {
+		return err
+	}
+
+	return t.Up(ctx, kb, ev)
+}
+
+func MetaState(mods Modifier) uint32 {
+	var meta uint32
+
+	if mods&ModShift != 0 {
+		meta |= scrcpy.AndroidMetaShiftOn | scrcpy.AndroidMetaShiftLeftOn
+	}
+
+	if mods&ModCtrl != 0 {
+		meta |= scrcpy.AndroidMetaCtrlOn | scrcpy.AndroidMetaCtrlLeftOn
+	}
+
+	if mods&ModAlt != 0 {
+		meta |= scrcpy.AndroidMetaAltOn | scrcpy.AndroidMetaAltLeftOn
+	}
+
+	if mods&ModMeta != 0 {
+		meta |= scrcpy.AndroidMetaMetaOn | scrcpy.AndroidMetaMetaLeftOn
+	}
+
+	return meta
+}
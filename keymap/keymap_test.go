@@ -0,0 +1,182 @@
+package keymap_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/keymap"
+)
+
+const (
+	metaShift = scrcpy.AndroidMetaShiftOn | scrcpy.AndroidMetaShiftLeftOn
+	metaCtrl  = scrcpy.AndroidMetaCtrlOn | scrcpy.AndroidMetaCtrlLeftOn
+	metaAlt   = scrcpy.AndroidMetaAltOn | scrcpy.AndroidMetaAltLeftOn
+	metaMeta  = scrcpy.AndroidMetaMetaOn | scrcpy.AndroidMetaMetaLeftOn
+)
+
+type keyEvent struct {
+	code   uint32
+	action byte
+	repeat uint32
+	meta   uint32
+}
+
+type fakeKeyboard struct {
+	events []keyEvent
+	err    error
+}
+
+func (k *fakeKeyboard) InjectKeycodeCtx(_ context.Context, keycode uint32, action byte, repeat, meta uint32) error {
+	if k.err != nil {
+		return k.err
+	}
+
+	k.events = append(k.events, keyEvent{keycode, action, repeat, meta})
+
+	return nil
+}
+
+func TestTranslate(t *testing.T) {
+	tr := keymap.New(nil)
+
+	tests := []struct {
+		ev   keymap.Event
+		want keymap.Stroke
+	}{
+		{keymap.Event{Rune: 'a'}, keymap.Stroke{Keycode: scrcpy.AndroidKeyA}},
+		{keymap.Event{Rune: 'A'}, keymap.Stroke{Keycode: scrcpy.AndroidKeyA, Meta: metaShift}},
+		{keymap.Event{Rune: 'c', Mods: keymap.ModCtrl}, keymap.Stroke{Keycode: scrcpy.AndroidKeyC, Meta: metaCtrl}},
+		{keymap.Event{Rune: '?', Mods: keymap.ModShift}, keymap.Stroke{Keycode: scrcpy.AndroidKeySlash, Meta: metaShift}},
+		{keymap.Event{Key: keymap.KeyEscape}, keymap.Stroke{Keycode: scrcpy.AndroidKeyEscape}},
+		{keymap.Event{Key: keymap.KeyBackspace, Mods: keymap.ModAlt}, keymap.Stroke{Keycode: scrcpy.AndroidKeyDel, Meta: metaAlt}},
+		{keymap.Event{Key: keymap.KeyF12}, keymap.Stroke{Keycode: scrcpy.AndroidKeyF12}},
+	}
+
+	for _, tt := range tests {
+		got, err := tr.Translate(tt.ev)
+		if err != nil {
+			t.Errorf("Translate(%+v): %v", tt.ev, err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Translate(%+v) = %+v, want %+v", tt.ev, got, tt.want)
+		}
+	}
+
+	for _, ev := range []keymap.Event{{Rune: 'é'}, {Key: keymap.KeyF12 + 1}} {
+		if _, err := tr.Translate(ev); !errors.Is(err, keymap.ErrUnmapped) {
+			t.Errorf("Translate(%+v) = %v, want ErrUnmapped", ev, err)
+		}
+	}
+}
+
+func TestMetaState(t *testing.T) {
+	if got := keymap.MetaState(0); got != scrcpy.AndroidMetaNone {
+		t.Errorf("MetaState(0) = %#x", got)
+	}
+
+	all := keymap.ModShift | keymap.ModCtrl | keymap.ModAlt | keymap.ModMeta
+	if got, want := keymap.MetaState(all), uint32(metaShift|metaCtrl|metaAlt|metaMeta); got != want {
+		t.Errorf("MetaState(all) = %#x, want %#x", got, want)
+	}
+}
+
+func TestDownUpRepeat(t *testing.T) {
+	tr := keymap.New(keymap.US)
+	kb := &fakeKeyboard{}
+	ctx := context.Background()
+	ev := keymap.Event{Rune: 'x', Mods: keymap.ModCtrl}
+
+	for range 3 {
+		if err := tr.Down(ctx, kb, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tr.Up(ctx, kb, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.Press(ctx, kb, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []keyEvent{
+		{scrcpy.AndroidKeyX, scrcpy.ActionDown, 0, metaCtrl},
+		{scrcpy.AndroidKeyX, scrcpy.ActionDown, 1, metaCtrl},
+		{scrcpy.AndroidKeyX, scrcpy.ActionDown, 2, metaCtrl},
+		{scrcpy.AndroidKeyX, scrcpy.ActionUp, 0, metaCtrl},
+		{scrcpy.AndroidKeyX, scrcpy.ActionDown, 0, metaCtrl},
+		{scrcpy.AndroidKeyX, scrcpy.ActionUp, 0, metaCtrl},
+	}
+
+	if !slices.Equal(kb.events, want) {
+		t.Fatalf("events = %v, want %v", kb.events, want)
+	}
+}
+
+func TestDownFailureNotHeld(t *testing.T) {
+	tr := keymap.New(nil)
+	kb := &fakeKeyboard{err: errors.New("closed")}
+	ctx := context.Background()
+	ev := keymap.Event{Key: keymap.KeyEnter}
+
+	if err := tr.Down(ctx, kb, ev); err == nil {
+		t.Fatal("Down succeeded on a failing keyboard")
+	}
+
+	kb.err = nil
+
+	if err := tr.Down(ctx, kb, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []keyEvent{{scrcpy.AndroidKeyEnter, scrcpy.ActionDown, 0, 0}}; !slices.Equal(kb.events, want) {
+		t.Fatalf("events = %v, want %v", kb.events, want)
+	}
+}
+
+func TestPressUnmapped(t *testing.T) {
+	kb := &fakeKeyboard{}
+
+	if err := keymap.New(nil).Press(context.Background(), kb, keymap.Event{Rune: '€'}); !errors.Is(err, keymap.ErrUnmapped) {
+		t.Fatalf("Press = %v, want ErrUnmapped", err)
+	}
+
+	if len(kb.events) != 0 {
+		t.Fatalf("events = %v, want none", kb.events)
+	}
+}
+
+func TestChain(t *testing.T) {
+	azerty := keymap.LayoutMap{
+		'a': {Keycode: scrcpy.AndroidKeyQ},
+		'é': {Keycode: scrcpy.AndroidKey2},
+	}
+	tr := keymap.New(keymap.Chain(azerty, keymap.US))
+
+	tests := []struct {
+		r    rune
+		want uint32
+	}{
+		{'a', scrcpy.AndroidKeyQ},
+		{'é', scrcpy.AndroidKey2},
+		{'b', scrcpy.AndroidKeyB},
+	}
+
+	for _, tt := range tests {
+		s, err := tr.Translate(keymap.Event{Rune: tt.r})
+		if err != nil || s.Keycode != tt.want {
+			t.Errorf("Translate(%q) = %+v, %v, want keycode %d", tt.r, s, err, tt.want)
+		}
+	}
+
+	if _, err := tr.Translate(keymap.Event{Rune: '€'}); !errors.Is(err, keymap.ErrUnmapped) {
+		t.Errorf("Translate('€') = %v, want ErrUnmapped", err)
+	}
+}
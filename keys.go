@@ -0,0 +1,215 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	keyRepeatTimeout  = 500 * time.Millisecond
+	keyRepeatDelay    = 50 * time.Millisecond
+	keyReleaseTimeout = time.Second
+)
+
+type Chord struct {
+	Modifiers []uint32
+	Keycode   uint32
+	Meta      uint32
+}
+
+var chordModifiers = map[string]struct {
+	keycode uint32
+	meta    uint32
+}{
+	"shift":   {AndroidKeyShiftLeft, AndroidMetaShiftOn | AndroidMetaShiftLeftOn},
+	"ctrl":    {AndroidKeyCtrlLeft, AndroidMetaCtrlOn | AndroidMetaCtrlLeftOn},
+	"control": {AndroidKeyCtrlLeft, AndroidMetaCtrlOn | AndroidMetaCtrlLeftOn},
+	"alt":     {AndroidKeyAltLeft, AndroidMetaAltOn | AndroidMetaAltLeftOn},
+	"meta":    {AndroidKeyMetaLeft, AndroidMetaMetaOn | AndroidMetaMetaLeftOn},
+	"super":   {AndroidKeyMetaLeft, AndroidMetaMetaOn | AndroidMetaMetaLeftOn},
+}
+
+func ParseChord(s string) (Chord, error) {
+	var ch Chord
+
+	parts := strings.Split(s, "+")
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+
+		if i < len(parts)-1 {
+			mod, ok := chordModifiers[strings.ToLower(part)]
+			if !ok {
+				return Chord{}, fmt.Errorf("%w: modifier %q", ErrUnknownKey, part)
+			}
+
+			ch.Modifiers = append(ch.Modifiers, mod.keycode)
+			ch.Meta |= mod.meta
+
+			continue
+		}
+
+		code, meta, ok := chordKey(part)
+		if !ok {
+			return Chord{}, fmt.Errorf("%w: %q", ErrUnknownKey, part)
+		}
+
+		ch.Keycode = code
+		ch.Meta |= meta
+	}
+
+	return ch, nil
+}
+
+func chordKey(s string) (keycode, meta uint32, ok bool) {
+	if code, ok := ParseKeycode(s); ok {
+		return code, AndroidMetaNone, true
+	}
+
+	if r := []rune(s); len(r) == 1 {
+		return RuneKeycode(r[0])
+	}
+
+	return AndroidKeyUnknown, AndroidMetaNone, false
+}
+
+func (ch Chord) String() string {
+	var parts []string
+
+	for _, code := range ch.Modifiers {
+		parts = append(parts, chordModifierName(code))
+	}
+
+	return strings.Join(append(parts, KeycodeName(ch.Keycode)), "+")
+}
+
+func (c *Client) PressKey(code, meta uint32) error {
+	return c.PressKeyCtx(context.Background(), code, meta)
+}
+
+func (c *Client) PressKeyCtx(ctx context.Context, code, meta uint32) error {
+	if err := c.InjectKeycodeCtx(ctx, code, ActionDown, 0, meta); err != nil {
+		return err
+	}
+
+	return c.InjectKeycodeCtx(ctx, code, ActionUp, 0, meta)
+}
+
+func (c *Client) LongPressKey(code uint32, d time.Duration) error {
+	return c.LongPressKeyCtx(context.Background(), code, d)
+}
+
+func (c *Client) LongPressKeyCtx(ctx context.Context, code uint32, d time.Duration) (err error) {
+	if err := c.InjectKeycodeCtx(ctx, code, ActionDown, 0, AndroidMetaNone); err != nil {
+		return err
+	}
+
+	defer func() {
+		rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), keyReleaseTimeout)
+		defer cancel()
+
+		if uerr := c.InjectKeycodeCtx(rctx, code, ActionUp, 0, AndroidMetaNone); err == nil {
+			err = uerr
+		}
+	}()
+
+	deadline := time.Now().Add(d)
+	next := keyRepeatTimeout
+
+	for repeat := uint32(1); ; repeat++ {
+		left := time.Until(deadline)
+		if left < next {
+			return sleepCtx(ctx, left)
+		}
+
+		if err := sleepCtx(ctx, next); err != nil {
+			return err
+		}
+
+		if err := c.InjectKeycodeCtx(ctx, code, ActionDown, repeat, AndroidMetaNone); err != nil {
+			return err
+		}
+
+		next = keyRepeatDelay
+	}
+}
+
+func (c *Client) PressChord(chord string) error {
+	return c.PressChordCtx(context.Background(), chord)
+}
+
+func (c *Client) PressChordCtx(ctx context.Context, chord string) error {
+	ch, err := ParseChord(chord)
+	if err != nil {
+		return err
+	}
+
+	return c.InjectChordCtx(ctx, ch)
+}
+
+func (c *Client) InjectChord(ch Chord) error {
+	return c.InjectChordCtx(context.Background(), ch)
+}
+
+func (c *Client) InjectChordCtx(ctx context.Context, ch Chord) error {
+	var meta uint32
+
+	for i, code := range ch.Modifiers {
+		meta |= chordMeta(code)
+
+		if err := c.InjectKeycodeCtx(ctx, code, ActionDown, 0, meta); err != nil {
+			return c.releaseChord(ctx, ch.Modifiers[:i], meta&^chordMeta(code), err)
+		}
+	}
+
+	if err := c.PressKeyCtx(ctx, ch.Keycode, ch.Meta); err != nil {
+		return c.releaseChord(ctx, ch.Modifiers, meta, err)
+	}
+
+	return c.releaseChord(ctx, ch.Modifiers, meta, nil)
+}
+
+func (c *Client) releaseChord(ctx context.Context, mods []uint32, meta uint32, err error) error {
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), keyReleaseTimeout)
+	defer cancel()
+
+	for i := len(mods) - 1; i >= 0; i-- {
+		meta &^= chordMeta(mods[i])
+
+		if uerr := c.InjectKeycodeCtx(rctx, mods[i], ActionUp, 0, meta); err == nil {
+			err = uerr
+		}
+	}
+
+	return err
+}
+
+func chordModifierName(code uint32) string {
+	switch code {
+	case AndroidKeyShiftLeft:
+		return "shift"
+	case AndroidKeyCtrlLeft:
+		return "ctrl"
+	case AndroidKeyAltLeft:
+		return "alt"
+	case AndroidKeyMetaLeft:
+		return "meta"
+	}
+
+	return KeycodeName(code)
+}
+
+func chordMeta(code uint32) uint32 {
+	for _, mod := range chordModifiers {
+		if mod.keycode == code {
+			return mod.meta
+		}
+	}
+
+	return AndroidMetaNone
+}
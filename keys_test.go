@@ -0,0 +1,165 @@
+package scrcpy_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+const (
+	metaShift = scrcpy.AndroidMetaShiftOn | scrcpy.AndroidMetaShiftLeftOn
+	metaCtrl  = scrcpy.AndroidMetaCtrlOn | scrcpy.AndroidMetaCtrlLeftOn
+	metaAlt   = scrcpy.AndroidMetaAltOn | scrcpy.AndroidMetaAltLeftOn
+)
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		in   string
+		want scrcpy.Chord
+		str  string
+	}{
+		{in: "HOME", want: scrcpy.Chord{Keycode: scrcpy.AndroidKeyHome}, str: "KEYCODE_HOME"},
+		{in: "keycode_volume_up", want: scrcpy.Chord{Keycode: scrcpy.AndroidKeyVolumeUp}, str: "KEYCODE_VOLUME_UP"},
+		{
+			in:   "Ctrl+Shift+a",
+			want: scrcpy.Chord{Modifiers: []uint32{scrcpy.AndroidKeyCtrlLeft, scrcpy.AndroidKeyShiftLeft}, Keycode: scrcpy.AndroidKeyA, Meta: metaCtrl | metaShift},
+			str:  "ctrl+shift+KEYCODE_A",
+		},
+		{
+			in:   "alt + TAB",
+			want: scrcpy.Chord{Modifiers: []uint32{scrcpy.AndroidKeyAltLeft}, Keycode: scrcpy.AndroidKeyTab, Meta: metaAlt},
+			str:  "alt+KEYCODE_TAB",
+		},
+		{in: "A", want: scrcpy.Chord{Keycode: scrcpy.AndroidKeyA}, str: "KEYCODE_A"},
+		{in: "?", want: scrcpy.Chord{Keycode: scrcpy.AndroidKeySlash, Meta: metaShift}, str: "KEYCODE_SLASH"},
+		{
+			in:   "ctrl++",
+			want: scrcpy.Chord{Modifiers: []uint32{scrcpy.AndroidKeyCtrlLeft}, Keycode: scrcpy.AndroidKeyPlus, Meta: metaCtrl},
+			str:  "ctrl+KEYCODE_PLUS",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := scrcpy.ParseChord(tt.in)
+		if err != nil {
+			t.Errorf("ParseChord(%q): %v", tt.in, err)
+
+			continue
+		}
+
+		if !slices.Equal(got.Modifiers, tt.want.Modifiers) || got.Keycode != tt.want.Keycode || got.Meta != tt.want.Meta {
+			t.Errorf("ParseChord(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+
+		if s := got.String(); s != tt.str {
+			t.Errorf("ParseChord(%q).String() = %q, want %q", tt.in, s, tt.str)
+		}
+	}
+
+	for _, in := range []string{"", "hyper+a", "ctrl+NOT_A_KEY", "ctrl+é"} {
+		if _, err := scrcpy.ParseChord(in); !errors.Is(err, scrcpy.ErrUnknownKey) {
+			t.Errorf("ParseChord(%q) = %v, want ErrUnknownKey", in, err)
+		}
+	}
+}
+
+func TestKeycodeNames(t *testing.T) {
+	for _, code := range scrcpy.Keycodes() {
+		name := scrcpy.KeycodeName(code)
+
+		if got, ok := scrcpy.ParseKeycode(name); !ok || got != code {
+			t.Errorf("ParseKeycode(%q) = %d, %t, want %d", name, got, ok, code)
+		}
+	}
+
+	if name := scrcpy.KeycodeName(100000); name != "KEYCODE_100000" {
+		t.Errorf("KeycodeName(100000) = %q", name)
+	}
+}
+
+type keyEvent struct {
+	action byte
+	code   uint32
+	repeat uint32
+	meta   uint32
+}
+
+func keyEvents(t *testing.T, srv *scrcpytest.Server, n int) []keyEvent {
+	t.Helper()
+
+	msgs, err := srv.WaitMessages(testContext(t), n)
+	if err != nil {
+		t.Fatalf("WaitMessages: %v", err)
+	}
+
+	events := make([]keyEvent, 0, len(msgs))
+
+	for _, msg := range msgs {
+		m, ok := msg.(*protocol.InjectKeycode)
+		if !ok {
+			t.Fatalf("unexpected message %v", msg)
+		}
+
+		events = append(events, keyEvent{m.Action, m.Keycode, m.Repeat, m.MetaState})
+	}
+
+	return events
+}
+
+func TestPressChord(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	if err := c.PressChordCtx(testContext(t), "ctrl+shift+z"); err != nil {
+		t.Fatalf("PressChord: %v", err)
+	}
+
+	want := []keyEvent{
+		{scrcpy.ActionDown, scrcpy.AndroidKeyCtrlLeft, 0, metaCtrl},
+		{scrcpy.ActionDown, scrcpy.AndroidKeyShiftLeft, 0, metaCtrl | metaShift},
+		{scrcpy.ActionDown, scrcpy.AndroidKeyZ, 0, metaCtrl | metaShift},
+		{scrcpy.ActionUp, scrcpy.AndroidKeyZ, 0, metaCtrl | metaShift},
+		{scrcpy.ActionUp, scrcpy.AndroidKeyShiftLeft, 0, metaCtrl},
+		{scrcpy.ActionUp, scrcpy.AndroidKeyCtrlLeft, 0, 0},
+	}
+
+	if got := keyEvents(t, srv, len(want)); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+func TestLongPressKey(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	start := time.Now()
+
+	if err := c.LongPressKeyCtx(testContext(t), scrcpy.AndroidKeyPower, 600*time.Millisecond); err != nil {
+		t.Fatalf("LongPressKey: %v", err)
+	}
+
+	if d := time.Since(start); d < 600*time.Millisecond {
+		t.Fatalf("LongPressKey returned after %v", d)
+	}
+
+	var got []keyEvent
+
+	for n := 3; len(got) == 0 || got[len(got)-1].action != scrcpy.ActionUp; n++ {
+		got = keyEvents(t, srv, n)
+	}
+
+	if got[0] != (keyEvent{scrcpy.ActionDown, scrcpy.AndroidKeyPower, 0, 0}) ||
+		got[1] != (keyEvent{scrcpy.ActionDown, scrcpy.AndroidKeyPower, 1, 0}) ||
+		got[len(got)-1] != (keyEvent{scrcpy.ActionUp, scrcpy.AndroidKeyPower, 0, 0}) {
+		t.Fatalf("events = %v", got)
+	}
+
+	for i, ev := range got[1 : len(got)-1] {
+		if ev.action != scrcpy.ActionDown || ev.repeat != uint32(i+1) {
+			t.Fatalf("repeat %d = %v", i, ev)
+		}
+	}
+}
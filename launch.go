@@ -0,0 +1,207 @@
+package scrcpy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/merzzzl/scrcpy-go/adb"
+)
+
+const (
+	connectAttempts = 100
+	connectDelay    = 100 * time.Millisecond
+	serverLogLimit  = 64 * 1024
+)
+
+func Launch(ctx context.Context, dev *adb.Device, serverPath string, opts ServerOptions) (*Client, error) {
+	if opts.SCID == NoSCID {
+		opts.SCID = rand.Int32N(0x7fffffff)
+	}
+
+	cmd, err := opts.Command(ServerRemotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pushServer(ctx, dev, serverPath); err != nil {
+		return nil, err
+	}
+
+	socketName := "localabstract:" + SocketName(opts.SCID)
+	dialOpts := opts.DialOptions()
+
+	if opts.TunnelForward {
+		local, err := dev.Forward(ctx, "tcp:0", socketName)
+		if err != nil {
+			return nil, fmt.Errorf("forward: %w", err)
+		}
+
+		defer func() { _ = dev.KillForward(context.WithoutCancel(ctx), local) }()
+
+		shell, sctx, err := startServer(ctx, dev, cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := connectForward(sctx, "127.0.0.1:"+strings.TrimPrefix(local, "tcp:"), dialOpts)
+		if err != nil {
+			_ = shell.Close()
+
+			return nil, serverErr(sctx, err)
+		}
+
+		c.closers = append(c.closers, shell)
+
+		return c, nil
+	}
+
+	ln, err := Listen(ctx, "127.0.0.1:0", WithDialOptions(dialOpts))
+	if err != nil {
+		return nil, err
+	}
+
+	defer ln.Close()
+
+	if _, err := dev.Reverse(ctx, socketName, "tcp:"+portOf(ln.Addr())); err != nil {
+		return nil, fmt.Errorf("reverse: %w", err)
+	}
+
+	defer func() { _ = dev.KillReverse(context.WithoutCancel(ctx), socketName) }()
+
+	shell, sctx, err := startServer(ctx, dev, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ln.Accept(sctx)
+	if err != nil {
+		_ = shell.Close()
+
+		return nil, serverErr(sctx, err)
+	}
+
+	c.closers = append(c.closers, shell)
+
+	return c, nil
+}
+
+func pushServer(ctx context.Context, dev *adb.Device, serverPath string) error {
+	f, err := os.Open(serverPath)
+	if err != nil {
+		return fmt.Errorf("open server: %w", err)
+	}
+
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat server: %w", err)
+	}
+
+	if err := dev.Push(ctx, f, ServerRemotePath, 0o644, st.ModTime()); err != nil {
+		return fmt.Errorf("push server: %w", err)
+	}
+
+	return nil
+}
+
+type serverExitError struct {
+	log []byte
+}
+
+func (e *serverExitError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrServerExited, bytes.TrimSpace(e.log))
+}
+
+func (e *serverExitError) Unwrap() error { return ErrServerExited }
+
+func startServer(ctx context.Context, dev *adb.Device, cmd string) (net.Conn, context.Context, error) {
+	shell, err := dev.Shell(ctx, cmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("start server: %w", err)
+	}
+
+	sctx, cancel := context.WithCancelCause(ctx)
+
+	go func() {
+		var log bytes.Buffer
+
+		_, _ = io.Copy(&limitedBuffer{buf: &log, limit: serverLogLimit}, shell)
+
+		cancel(&serverExitError{log: log.Bytes()})
+	}()
+
+	return shell, sctx, nil
+}
+
+func connectForward(ctx context.Context, addr string, opts DialOptions) (*Client, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+
+	for range connectAttempts {
+		c, err := dialFirst(ctx, addr, opts)
+		if err == nil {
+			if err := c.dialRest(ctx, addr); err != nil {
+				_ = c.Close()
+
+				return nil, fmt.Errorf("connect: %w", err)
+			}
+
+			return c, nil
+		}
+
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(connectDelay):
+		}
+	}
+
+	return nil, fmt.Errorf("connect: %w", lastErr)
+}
+
+func serverErr(ctx context.Context, err error) error {
+	var exitErr *serverExitError
+	if errors.As(context.Cause(ctx), &exitErr) {
+		return exitErr
+	}
+
+	return err
+}
+
+func portOf(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return strconv.Itoa(tcp.Port)
+	}
+
+	_, port, _ := net.SplitHostPort(addr.String())
+
+	return port
+}
+
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
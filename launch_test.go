@@ -0,0 +1,71 @@
+package scrcpy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnectForwardRetriesFirstSocket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	opts := newDialOptions(DefaultDialOptions(), []DialOption{
+		WithAudio(false), WithDeviceMeta(false), WithCodecMeta(false),
+	})
+
+	served := make(chan []net.Conn, 1)
+
+	go func() {
+		for range 2 {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+
+		video, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		_, _ = video.Write([]byte{0})
+
+		control, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		served <- []net.Conn{video, control}
+	}()
+
+	c, err := connectForward(ctx, ln.Addr().String(), opts)
+	if err != nil {
+		t.Fatalf("connectForward: %v", err)
+	}
+
+	defer c.Close()
+
+	select {
+	case conns := <-served:
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	case <-ctx.Done():
+		t.Fatal("server did not see the control socket")
+	}
+
+	if !c.handshake.Video || !c.handshake.Control {
+		t.Fatalf("handshake = %+v", c.handshake)
+	}
+}
@@ -0,0 +1,80 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+type Listener struct {
+	mutex sync.Mutex
+	ln    net.Listener
+	opts  DialOptions
+}
+
+func Listen(ctx context.Context, addr string, opts ...DialOption) (*Listener, error) {
+	base := DefaultDialOptions()
+	base.SendDummyByte = false
+
+	o := newDialOptions(base, opts)
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	return &Listener{
+		ln:   ln,
+		opts: o,
+	}, nil
+}
+
+func (l *Listener) Addr() net.Addr { return l.ln.Addr() }
+
+func (l *Listener) Close() error { return l.ln.Close() }
+
+func (l *Listener) Accept(ctx context.Context) (*Client, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	dl, ok := l.ln.(interface{ SetDeadline(t time.Time) error })
+	if ok {
+		_ = dl.SetDeadline(time.Time{})
+
+		stop := context.AfterFunc(ctx, func() {
+			_ = dl.SetDeadline(time.Now())
+		})
+		defer stop()
+	}
+
+	c := &Client{opts: l.opts}
+
+	for _, s := range c.sockets() {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			_ = c.Close()
+
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+
+			return nil, fmt.Errorf("%s accept: %w", s.name, err)
+		}
+
+		*s.conn = conn
+	}
+
+	if err := c.open(ctx); err != nil {
+		_ = c.Close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
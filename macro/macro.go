@@ -0,0 +1,258 @@
+package macro
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+const formatVersion = 1
+
+var ErrUnsupportedVersion = errors.New("unsupported macro version")
+
+type Event struct {
+	Offset  time.Duration
+	Message protocol.ControlMessage
+}
+
+type Recording struct {
+	DeviceName string
+	Width      uint32
+	Height     uint32
+	Events     []Event
+}
+
+type Recorder struct {
+	mutex sync.Mutex
+	start time.Time
+	rec   Recording
+}
+
+func NewRecorder(hs scrcpy.Handshake) *Recorder {
+	return &Recorder{
+		start: time.Now(),
+		rec: Recording{
+			DeviceName: hs.DeviceName,
+			Width:      hs.Width,
+			Height:     hs.Height,
+		},
+	}
+}
+
+func Record(c *scrcpy.Client) (*Recorder, func()) {
+	hs := c.GetHandshake()
+	hs.Width, hs.Height = c.FrameSize()
+
+	r := NewRecorder(hs)
+
+	return r, c.OnSend(r.Add)
+}
+
+func (r *Recorder) Add(msg protocol.ControlMessage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rec.Events = append(r.rec.Events, Event{
+		Offset:  time.Since(r.start),
+		Message: msg,
+	})
+}
+
+func (r *Recorder) Recording() *Recording {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rec := r.rec
+	rec.Events = append([]Event(nil), r.rec.Events...)
+
+	return &rec
+}
+
+type fileHeader struct {
+	Version    int         `json:"version"`
+	DeviceName string      `json:"deviceName,omitempty"`
+	Width      uint32      `json:"width"`
+	Height     uint32      `json:"height"`
+	Events     []fileEvent `json:"events"`
+}
+
+type fileEvent struct {
+	OffsetUs int64  `json:"offsetUs"`
+	Message  string `json:"message"`
+	Desc     string `json:"desc,omitempty"`
+}
+
+func (rec *Recording) Save(w io.Writer) error {
+	f := fileHeader{
+		Version:    formatVersion,
+		DeviceName: rec.DeviceName,
+		Width:      rec.Width,
+		Height:     rec.Height,
+		Events:     make([]fileEvent, 0, len(rec.Events)),
+	}
+
+	for _, ev := range rec.Events {
+		raw, err := ev.Message.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", ev.Message.Type(), err)
+		}
+
+		f.Events = append(f.Events, fileEvent{
+			OffsetUs: ev.Offset.Microseconds(),
+			Message:  base64.StdEncoding.EncodeToString(raw),
+			Desc:     ev.Message.String(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("encode macro: %w", err)
+	}
+
+	return nil
+}
+
+func Load(r io.Reader) (*Recording, error) {
+	var f fileHeader
+
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("decode macro: %w", err)
+	}
+
+	if f.Version != formatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, f.Version)
+	}
+
+	rec := &Recording{
+		DeviceName: f.DeviceName,
+		Width:      f.Width,
+		Height:     f.Height,
+		Events:     make([]Event, 0, len(f.Events)),
+	}
+
+	for i, ev := range f.Events {
+		raw, err := base64.StdEncoding.DecodeString(ev.Message)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+
+		msg, err := protocol.UnmarshalControlMessage(raw)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+
+		rec.Events = append(rec.Events, Event{
+			Offset:  time.Duration(ev.OffsetUs) * time.Microsecond,
+			Message: msg,
+		})
+	}
+
+	return rec, nil
+}
+
+func Replay(ctx context.Context, c *scrcpy.Client, rec *Recording, speed float64) (err error) {
+	down := make(map[uint64]*protocol.InjectTouchEvent)
+
+	defer func() {
+		if rerr := release(context.WithoutCancel(ctx), c, down); err == nil {
+			err = rerr
+		}
+	}()
+
+	start := time.Now()
+
+	for i, ev := range rec.Events {
+		if speed > 0 {
+			if wait := time.Until(start.Add(time.Duration(float64(ev.Offset) / speed))); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		width, height := c.FrameSize()
+		msg := rescale(ev.Message, width, height)
+
+		if err := c.Send(ctx, msg); err != nil {
+			return fmt.Errorf("replay event %d (%s): %w", i, ev.Message.Type(), err)
+		}
+
+		if m, ok := msg.(*protocol.InjectTouchEvent); ok {
+			switch m.Action {
+			case scrcpy.ActionUp:
+				delete(down, m.PointerID)
+			case scrcpy.ActionDown:
+				down[m.PointerID] = m
+			case scrcpy.ActionMove:
+				if _, ok := down[m.PointerID]; ok {
+					down[m.PointerID] = m
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func release(ctx context.Context, c *scrcpy.Client, down map[uint64]*protocol.InjectTouchEvent) error {
+	var errs []error
+
+	for _, id := range slices.Sorted(maps.Keys(down)) {
+		up := *down[id]
+		up.Action = scrcpy.ActionUp
+		up.Pressure = 0
+		up.ActionButton = up.Buttons & -up.Buttons
+		up.Buttons = 0
+
+		if err := c.Send(ctx, &up); err != nil && !errors.Is(err, scrcpy.ErrClosed) {
+			errs = append(errs, fmt.Errorf("release pointer %d: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func rescale(msg protocol.ControlMessage, width, height uint32) protocol.ControlMessage {
+	switch m := msg.(type) {
+	case *protocol.InjectTouchEvent:
+		out := *m
+		out.X = scale(m.X, m.ScreenWidth, width)
+		out.Y = scale(m.Y, m.ScreenHeight, height)
+		out.ScreenWidth = uint16(width)
+		out.ScreenHeight = uint16(height)
+
+		return &out
+	case *protocol.InjectScrollEvent:
+		out := *m
+		out.X = int32(scale(uint32(max(m.X, 0)), m.ScreenWidth, width))
+		out.Y = int32(scale(uint32(max(m.Y, 0)), m.ScreenHeight, height))
+		out.ScreenWidth = uint16(width)
+		out.ScreenHeight = uint16(height)
+
+		return &out
+	default:
+		return msg
+	}
+}
+
+func scale(v uint32, from uint16, to uint32) uint32 {
+	if from == 0 || uint32(from) == to {
+		return v
+	}
+
+	return uint32(uint64(v) * uint64(to) / uint64(from))
+}
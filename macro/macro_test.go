@@ -0,0 +1,267 @@
+package macro_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/macro"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return ctx
+}
+
+func dialTest(t *testing.T, cfg scrcpytest.Config) (*scrcpytest.Server, *scrcpy.Client) {
+	t.Helper()
+
+	ctx := testContext(t)
+
+	srv, err := scrcpytest.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	t.Cleanup(func() { _ = srv.Close() })
+
+	c, err := srv.Dial(ctx)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := srv.WaitConnected(ctx); err != nil {
+		t.Fatalf("WaitConnected: %v", err)
+	}
+
+	return srv, c
+}
+
+func touch(action byte, id uint64, x, y uint32) *protocol.InjectTouchEvent {
+	return &protocol.InjectTouchEvent{
+		Action:       action,
+		PointerID:    id,
+		X:            x,
+		Y:            y,
+		ScreenWidth:  1080,
+		ScreenHeight: 1920,
+		Pressure:     0xffff,
+		ActionButton: scrcpy.ButtonPrimary,
+		Buttons:      scrcpy.ButtonPrimary,
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	rec := &macro.Recording{
+		DeviceName: "pixel",
+		Width:      1080,
+		Height:     1920,
+		Events: []macro.Event{
+			{Offset: 0, Message: touch(scrcpy.ActionDown, 1, 100, 200)},
+			{Offset: 16 * time.Millisecond, Message: touch(scrcpy.ActionMove, 1, 110, 210)},
+			{Offset: 32 * time.Millisecond, Message: touch(scrcpy.ActionUp, 1, 120, 220)},
+			{Offset: time.Second, Message: &protocol.InjectText{Text: "héllo"}},
+			{Offset: 2 * time.Second, Message: &protocol.InjectKeycode{Action: scrcpy.ActionDown, Keycode: scrcpy.AndroidKeyHome}},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	if err := rec.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := macro.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, rec) {
+		t.Fatalf("Load = %+v, want %+v", got, rec)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := macro.Load(strings.NewReader(`{"version": 2, "events": []}`)); !errors.Is(err, macro.ErrUnsupportedVersion) {
+		t.Errorf("Load(version 2) = %v, want ErrUnsupportedVersion", err)
+	}
+
+	if _, err := macro.Load(strings.NewReader(`{"version": 1, "events": [{"offsetUs": 0, "message": "/w=="}]}`)); !errors.Is(err, protocol.ErrUnknownMessage) {
+		t.Errorf("Load(unknown message) = %v, want ErrUnknownMessage", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	ctx := testContext(t)
+
+	var seen []protocol.ControlMessage
+
+	c.OnSend(func(msg protocol.ControlMessage) { seen = append(seen, msg) })
+
+	r, stop := macro.Record(c)
+
+	if err := c.InjectTextCtx(ctx, "one"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.InjectTextCtx(ctx, "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	stop()
+
+	if err := c.InjectTextCtx(ctx, "three"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := srv.WaitMessages(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := r.Recording()
+
+	if rec.DeviceName != "scrcpytest" || rec.Width != 1080 || rec.Height != 1920 {
+		t.Fatalf("recording header = %q %dx%d", rec.DeviceName, rec.Width, rec.Height)
+	}
+
+	if len(rec.Events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(rec.Events))
+	}
+
+	for i, want := range []string{"one", "two"} {
+		if m, ok := rec.Events[i].Message.(*protocol.InjectText); !ok || m.Text != want {
+			t.Fatalf("event %d = %v, want %q", i, rec.Events[i].Message, want)
+		}
+	}
+
+	if rec.Events[1].Offset < rec.Events[0].Offset {
+		t.Fatalf("offsets not monotonic: %v, %v", rec.Events[0].Offset, rec.Events[1].Offset)
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("existing OnSend hook saw %d messages, want 3", len(seen))
+	}
+}
+
+func TestReplayRescale(t *testing.T) {
+	cfg := scrcpytest.DefaultConfig()
+	cfg.Width, cfg.Height = 540, 960
+	srv, c := dialTest(t, cfg)
+	ctx := testContext(t)
+
+	rec := &macro.Recording{
+		Width:  1080,
+		Height: 1920,
+		Events: []macro.Event{
+			{Message: touch(scrcpy.ActionDown, 1, 100, 200)},
+			{Message: touch(scrcpy.ActionUp, 1, 1080, 1920)},
+			{Message: &protocol.InjectScrollEvent{X: 500, Y: -5, ScreenWidth: 1080, ScreenHeight: 1920, VScroll: 1}},
+		},
+	}
+
+	if err := macro.Replay(ctx, c, rec, 0); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	msgs, err := srv.WaitMessages(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	down, up := msgs[0].(*protocol.InjectTouchEvent), msgs[1].(*protocol.InjectTouchEvent)
+
+	if down.X != 50 || down.Y != 100 || down.ScreenWidth != 540 || down.ScreenHeight != 960 {
+		t.Errorf("down = %v", down)
+	}
+
+	if up.X != 540 || up.Y != 960 {
+		t.Errorf("up = %v", up)
+	}
+
+	if scroll := msgs[2].(*protocol.InjectScrollEvent); scroll.X != 250 || scroll.Y != 0 || scroll.ScreenWidth != 540 {
+		t.Errorf("scroll = %v", scroll)
+	}
+}
+
+func TestReplayTiming(t *testing.T) {
+	_, c := dialTest(t, scrcpytest.DefaultConfig())
+	ctx := testContext(t)
+
+	rec := &macro.Recording{
+		Events: []macro.Event{
+			{Offset: 0, Message: &protocol.InjectText{Text: "a"}},
+			{Offset: 200 * time.Millisecond, Message: &protocol.InjectText{Text: "b"}},
+			{Offset: 400 * time.Millisecond, Message: &protocol.InjectText{Text: "c"}},
+		},
+	}
+
+	tests := []struct {
+		speed    float64
+		min, max time.Duration
+	}{
+		{speed: 1, min: 400 * time.Millisecond, max: 600 * time.Millisecond},
+		{speed: 4, min: 100 * time.Millisecond, max: 300 * time.Millisecond},
+		{speed: 0, min: 0, max: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		start := time.Now()
+
+		if err := macro.Replay(ctx, c, rec, tt.speed); err != nil {
+			t.Fatalf("Replay(speed %v): %v", tt.speed, err)
+		}
+
+		if d := time.Since(start); d < tt.min || d > tt.max {
+			t.Errorf("Replay(speed %v) took %v, want %v..%v", tt.speed, d, tt.min, tt.max)
+		}
+	}
+}
+
+func TestReplayCancelReleasesPointers(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	rec := &macro.Recording{
+		Events: []macro.Event{
+			{Offset: 0, Message: touch(scrcpy.ActionDown, 1, 100, 200)},
+			{Offset: 0, Message: touch(scrcpy.ActionDown, 2, 300, 400)},
+			{Offset: 0, Message: touch(scrcpy.ActionUp, 2, 300, 400)},
+			{Offset: 0, Message: touch(scrcpy.ActionMove, 1, 150, 250)},
+			{Offset: time.Minute, Message: touch(scrcpy.ActionUp, 1, 150, 250)},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(testContext(t), 100*time.Millisecond)
+	defer cancel()
+
+	if err := macro.Replay(ctx, c, rec, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Replay = %v, want DeadlineExceeded", err)
+	}
+
+	msgs, err := srv.WaitMessages(testContext(t), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	up, ok := msgs[4].(*protocol.InjectTouchEvent)
+	if !ok || up.Action != scrcpy.ActionUp || up.PointerID != 1 || up.X != 150 || up.Y != 250 || up.Buttons != 0 || up.ActionButton != scrcpy.ButtonPrimary {
+		t.Fatalf("release = %v", msgs[4])
+	}
+
+	if len(srv.Messages()) != 5 {
+		t.Fatalf("got %d messages, want 5", len(srv.Messages()))
+	}
+}
@@ -0,0 +1,123 @@
+package scrcpy
+
+import (
+	"fmt"
+	"time"
+)
+
+const NoSCID int32 = -1
+
+type DialOptions struct {
+	Video            bool
+	Audio            bool
+	Control          bool
+	SendDummyByte    bool
+	SendDeviceMeta   bool
+	SendCodecMeta    bool
+	SCID             int32
+	DialTimeout      time.Duration
+	HandshakeTimeout time.Duration
+	WriteTimeout     time.Duration
+	WriteBatch       int
+}
+
+type DialOption func(*DialOptions)
+
+func DefaultDialOptions() DialOptions {
+	return DialOptions{
+		Video:            true,
+		Control:          true,
+		SendDummyByte:    true,
+		SendDeviceMeta:   true,
+		SendCodecMeta:    true,
+		SCID:             NoSCID,
+		DialTimeout:      5 * time.Second,
+		HandshakeTimeout: 5 * time.Second,
+		WriteTimeout:     5 * time.Second,
+		WriteBatch:       16,
+	}
+}
+
+func WithVideo(enabled bool) DialOption {
+	return func(o *DialOptions) { o.Video = enabled }
+}
+
+func WithAudio(enabled bool) DialOption {
+	return func(o *DialOptions) { o.Audio = enabled }
+}
+
+func WithControl(enabled bool) DialOption {
+	return func(o *DialOptions) { o.Control = enabled }
+}
+
+func WithDummyByte(enabled bool) DialOption {
+	return func(o *DialOptions) { o.SendDummyByte = enabled }
+}
+
+func WithDeviceMeta(enabled bool) DialOption {
+	return func(o *DialOptions) { o.SendDeviceMeta = enabled }
+}
+
+func WithCodecMeta(enabled bool) DialOption {
+	return func(o *DialOptions) { o.SendCodecMeta = enabled }
+}
+
+func WithSCID(scid int32) DialOption {
+	return func(o *DialOptions) { o.SCID = scid }
+}
+
+func WithDialTimeout(d time.Duration) DialOption {
+	return func(o *DialOptions) { o.DialTimeout = d }
+}
+
+func WithHandshakeTimeout(d time.Duration) DialOption {
+	return func(o *DialOptions) { o.HandshakeTimeout = d }
+}
+
+func WithWriteTimeout(d time.Duration) DialOption {
+	return func(o *DialOptions) { o.WriteTimeout = d }
+}
+
+func WithWriteBatch(n int) DialOption {
+	return func(o *DialOptions) { o.WriteBatch = n }
+}
+
+func WithDialOptions(opts DialOptions) DialOption {
+	return func(o *DialOptions) { *o = opts }
+}
+
+func (o *DialOptions) SocketName() string {
+	return SocketName(o.SCID)
+}
+
+func (o *DialOptions) validate() error {
+	if !o.Video && !o.Audio && !o.Control {
+		return ErrNoSockets
+	}
+
+	if o.SCID < NoSCID {
+		return fmt.Errorf("%w: scid %d", ErrInvalidOption, o.SCID)
+	}
+
+	if o.WriteBatch < 0 {
+		return fmt.Errorf("%w: write batch %d", ErrInvalidOption, o.WriteBatch)
+	}
+
+	return nil
+}
+
+func SocketName(scid int32) string {
+	if scid == NoSCID {
+		return socketNamePrefix
+	}
+
+	return fmt.Sprintf("%s_%08x", socketNamePrefix, scid)
+}
+
+func newDialOptions(o DialOptions, opts []DialOption) DialOptions {
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
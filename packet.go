@@ -0,0 +1,128 @@
+package scrcpy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type PacketHandler func(context.Context, Packet) error
+
+type Packet struct {
+	PTS        uint64
+	IsConfig   bool
+	IsKeyFrame bool
+	Data       []byte
+}
+
+func (p Packet) Timestamp() time.Duration {
+	return time.Duration(p.PTS) * time.Microsecond
+}
+
+func readPacket(r io.Reader, hdr []byte) (Packet, error) {
+	if err := readExactly(r, frameHeaderLen, hdr); err != nil {
+		return Packet{}, fmt.Errorf("header: %w", err)
+	}
+
+	ptsFlags := binary.BigEndian.Uint64(hdr[:8])
+	size := binary.BigEndian.Uint32(hdr[8:12])
+
+	if size > maxPacketLen {
+		return Packet{}, fmt.Errorf("%w: packet size %d exceeds %d", ErrProtocol, size, maxPacketLen)
+	}
+
+	pkt := Packet{
+		PTS:        ptsFlags & packetPTSMask,
+		IsConfig:   ptsFlags&packetFlagConfig != 0,
+		IsKeyFrame: ptsFlags&packetFlagKeyFrame != 0,
+		Data:       make([]byte, size),
+	}
+
+	if err := readExactly(r, int(size), pkt.Data); err != nil {
+		return Packet{}, fmt.Errorf("payload: %w", err)
+	}
+
+	return pkt, nil
+}
+
+func readStream(ctx context.Context, name string, conn net.Conn, handler func(io.Reader) error, packets PacketHandler) error {
+	hdr := make([]byte, frameHeaderLen)
+	pr, pw := io.Pipe()
+	eg, gctx := errgroup.WithContext(ctx)
+
+	_ = conn.SetReadDeadline(time.Time{})
+
+	stop := context.AfterFunc(gctx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	eg.Go(func() error {
+		if handler == nil {
+			return nil
+		}
+
+		defer pr.Close()
+
+		if err := handler(pr); err != nil {
+			return fmt.Errorf("%s handler: %w", name, err)
+		}
+
+		return nil
+	})
+
+	eg.Go(func() error {
+		defer pw.Close()
+
+		forward := handler != nil
+
+		for {
+			pkt, err := readPacket(conn, hdr)
+			if err != nil {
+				if gctx.Err() != nil {
+					return nil
+				}
+
+				return fmt.Errorf("read %s: %w", name, err)
+			}
+
+			if packets != nil {
+				if err := packets(gctx, pkt); err != nil {
+					return fmt.Errorf("%s packet handler: %w", name, err)
+				}
+			}
+
+			if !forward || len(pkt.Data) == 0 {
+				continue
+			}
+
+			if _, err := pw.Write(pkt.Data); err != nil {
+				if gctx.Err() != nil {
+					return nil
+				}
+
+				if errors.Is(err, io.ErrClosedPipe) {
+					forward = false
+
+					continue
+				}
+
+				return fmt.Errorf("write %s: %w", name, err)
+			}
+		}
+	})
+
+	eg.Go(func() error {
+		<-gctx.Done()
+
+		return errors.Join(pw.Close(), pr.Close())
+	})
+
+	return eg.Wait()
+}
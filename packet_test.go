@@ -0,0 +1,119 @@
+package scrcpy_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func TestPackets(t *testing.T) {
+	cfg := scrcpytest.DefaultConfig()
+	cfg.Audio = true
+	srv, c := dialTest(t, cfg)
+
+	sent := []scrcpy.Packet{
+		{IsConfig: true, Data: []byte{0, 0, 0, 1, 0x67}},
+		{PTS: 0, IsKeyFrame: true, Data: []byte("key")},
+		{PTS: 16_666, Data: []byte("delta")},
+		{PTS: 1<<62 - 1, Data: []byte("max")},
+		{PTS: 50_000, Data: nil},
+	}
+
+	var (
+		mutex sync.Mutex
+		video []scrcpy.Packet
+	)
+
+	audio := make(chan scrcpy.Packet, 1)
+
+	done := make(chan struct{})
+
+	c.SetVideoPacketHandler(func(_ context.Context, pkt scrcpy.Packet) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		video = append(video, pkt)
+		if len(video) == len(sent) {
+			close(done)
+		}
+
+		return nil
+	})
+
+	c.SetAudioPacketHandler(func(_ context.Context, pkt scrcpy.Packet) error {
+		audio <- pkt
+
+		return nil
+	})
+
+	stream := make(chan []byte, 1)
+
+	c.SetVideoHandler(func(r io.Reader) error {
+		var want int
+		for _, pkt := range sent {
+			want += len(pkt.Data)
+		}
+
+		buf := make([]byte, want)
+		_, err := io.ReadFull(r, buf)
+		stream <- buf
+
+		return err
+	})
+
+	serveTest(t, c)
+
+	ctx := testContext(t)
+
+	if err := srv.StreamVideo(ctx, sent); err != nil {
+		t.Fatalf("StreamVideo: %v", err)
+	}
+
+	if err := srv.SendAudio(scrcpy.Packet{PTS: 20_000, IsKeyFrame: true, Data: []byte("opus")}); err != nil {
+		t.Fatalf("SendAudio: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for video packets")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, pkt := range video {
+		want := sent[i]
+		if pkt.PTS != want.PTS || pkt.IsConfig != want.IsConfig || pkt.IsKeyFrame != want.IsKeyFrame || !bytes.Equal(pkt.Data, want.Data) {
+			t.Errorf("video packet %d = %+v, want %+v", i, pkt, want)
+		}
+	}
+
+	if got := video[2].Timestamp(); got != 16_666*time.Microsecond {
+		t.Errorf("Timestamp = %v", got)
+	}
+
+	select {
+	case got := <-stream:
+		if want := "\x00\x00\x00\x01\x67keydeltamax"; string(got) != want {
+			t.Errorf("video stream = %q, want %q", got, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for video stream")
+	}
+
+	select {
+	case pkt := <-audio:
+		if pkt.PTS != 20_000 || !pkt.IsKeyFrame || pkt.IsConfig || string(pkt.Data) != "opus" {
+			t.Errorf("audio packet = %+v", pkt)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for audio packet")
+	}
+}
@@ -0,0 +1,272 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type ControlMessage interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	fmt.Stringer
+	Type() ControlMessageType
+	encode(e *encoder) error
+	decode(d *decoder)
+}
+
+type DeviceMessage interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	fmt.Stringer
+	Type() DeviceMessageType
+	encode(e *encoder) error
+	decode(d *decoder)
+}
+
+func NewControlMessage(t ControlMessageType) (ControlMessage, error) {
+	switch t {
+	case CtrlInjectKeycode:
+		return &InjectKeycode{}, nil
+	case CtrlInjectText:
+		return &InjectText{}, nil
+	case CtrlInjectTouchEvent:
+		return &InjectTouchEvent{}, nil
+	case CtrlInjectScrollEvent:
+		return &InjectScrollEvent{}, nil
+	case CtrlBackOrScreenOn:
+		return &BackOrScreenOn{}, nil
+	case CtrlExpandNotificationPanel:
+		return &ExpandNotificationPanel{}, nil
+	case CtrlExpandSettingsPanel:
+		return &ExpandSettingsPanel{}, nil
+	case CtrlCollapsePanels:
+		return &CollapsePanels{}, nil
+	case CtrlGetClipboard:
+		return &GetClipboard{}, nil
+	case CtrlSetClipboard:
+		return &SetClipboard{}, nil
+	case CtrlSetDisplayPower:
+		return &SetDisplayPower{}, nil
+	case CtrlRotateDevice:
+		return &RotateDevice{}, nil
+	case CtrlUhidCreate:
+		return &UhidCreate{}, nil
+	case CtrlUhidInput:
+		return &UhidInput{}, nil
+	case CtrlUhidDestroy:
+		return &UhidDestroy{}, nil
+	case CtrlOpenHardKeyboardSettings:
+		return &OpenHardKeyboardSettings{}, nil
+	case CtrlStartApp:
+		return &StartApp{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMessage, t)
+	}
+}
+
+func NewDeviceMessage(t DeviceMessageType) (DeviceMessage, error) {
+	switch t {
+	case DeviceClipboard:
+		return &Clipboard{}, nil
+	case DeviceAckClipboard:
+		return &AckClipboard{}, nil
+	case DeviceUhidOutput:
+		return &UhidOutput{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMessage, t)
+	}
+}
+
+func ReadControlMessage(r io.Reader) (ControlMessage, error) {
+	d := &decoder{r: r}
+
+	t := ControlMessageType(d.u8())
+	if d.err != nil {
+		return nil, fmt.Errorf("type: %w", d.err)
+	}
+
+	m, err := NewControlMessage(t)
+	if err != nil {
+		return nil, err
+	}
+
+	m.decode(d)
+
+	if d.err != nil {
+		return nil, fmt.Errorf("%s: %w", t, unexpectedEOF(d.err))
+	}
+
+	return m, nil
+}
+
+func ReadDeviceMessage(r io.Reader) (DeviceMessage, error) {
+	d := &decoder{r: r}
+
+	t := DeviceMessageType(d.u8())
+	if d.err != nil {
+		return nil, fmt.Errorf("type: %w", d.err)
+	}
+
+	m, err := NewDeviceMessage(t)
+	if err != nil {
+		return nil, err
+	}
+
+	m.decode(d)
+
+	if d.err != nil {
+		return nil, fmt.Errorf("%s: %w", t, unexpectedEOF(d.err))
+	}
+
+	return m, nil
+}
+
+func UnmarshalControlMessage(b []byte) (ControlMessage, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("%w: empty message", ErrProtocol)
+	}
+
+	m, err := NewControlMessage(ControlMessageType(b[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func UnmarshalDeviceMessage(b []byte) (DeviceMessage, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("%w: empty message", ErrProtocol)
+	}
+
+	m, err := NewDeviceMessage(DeviceMessageType(b[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func marshal(t byte, encode func(e *encoder) error) ([]byte, error) {
+	e := &encoder{}
+	e.u8(t)
+
+	if err := encode(e); err != nil {
+		return nil, err
+	}
+
+	return e.buf, nil
+}
+
+func unmarshal(b []byte, t byte, name fmt.Stringer, decode func(d *decoder)) error {
+	r := bytes.NewReader(b)
+	d := &decoder{r: r}
+
+	if got := d.u8(); d.err == nil && got != t {
+		return fmt.Errorf("%w: %s: unexpected type %d", ErrProtocol, name, got)
+	}
+
+	decode(d)
+
+	if d.err != nil {
+		return fmt.Errorf("%s: %w", name, unexpectedEOF(d.err))
+	}
+
+	if r.Len() != 0 {
+		return fmt.Errorf("%w: %s: %d bytes", ErrTrailingData, name, r.Len())
+	}
+
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) u8(v byte) { e.buf = append(e.buf, v) }
+
+func (e *encoder) u16(v uint16) { e.buf = binary.BigEndian.AppendUint16(e.buf, v) }
+
+func (e *encoder) u32(v uint32) { e.buf = binary.BigEndian.AppendUint32(e.buf, v) }
+
+func (e *encoder) u64(v uint64) { e.buf = binary.BigEndian.AppendUint64(e.buf, v) }
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.u8(1)
+	} else {
+		e.u8(0)
+	}
+}
+
+func (e *encoder) bytes(b []byte) { e.buf = append(e.buf, b...) }
+
+type decoder struct {
+	r   io.Reader
+	err error
+	tmp [8]byte
+}
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return d.tmp[:n]
+	}
+
+	if _, err := io.ReadFull(d.r, d.tmp[:n]); err != nil {
+		d.err = err
+	}
+
+	return d.tmp[:n]
+}
+
+func (d *decoder) u8() byte { return d.read(1)[0] }
+
+func (d *decoder) u16() uint16 { return binary.BigEndian.Uint16(d.read(2)) }
+
+func (d *decoder) u32() uint32 { return binary.BigEndian.Uint32(d.read(4)) }
+
+func (d *decoder) u64() uint64 { return binary.BigEndian.Uint64(d.read(8)) }
+
+func (d *decoder) bool() bool { return d.u8() != 0 }
+
+func (d *decoder) bytes(n, limit uint32) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n > limit {
+		d.err = fmt.Errorf("%w: length %d exceeds %d", ErrProtocol, n, limit)
+
+		return nil
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		d.err = err
+
+		return nil
+	}
+
+	return buf
+}
@@ -0,0 +1,271 @@
+package protocol_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+var controlMessages = []protocol.ControlMessage{
+	&protocol.InjectKeycode{Action: protocol.ActionUp, Keycode: 66, Repeat: 3, MetaState: 0x3041},
+	&protocol.InjectText{Text: "héllo, мир"},
+	&protocol.InjectTouchEvent{Action: protocol.ActionMove, PointerID: 1<<64 - 3, X: 540, Y: 1170, ScreenWidth: 1080, ScreenHeight: 2340, Pressure: 0xffff, ActionButton: 1, Buttons: 3},
+	&protocol.InjectScrollEvent{X: -1, Y: 200, ScreenWidth: 1080, ScreenHeight: 2340, HScroll: -32768, VScroll: 16384, Buttons: 4},
+	&protocol.BackOrScreenOn{Action: protocol.ActionDown},
+	&protocol.ExpandNotificationPanel{},
+	&protocol.ExpandSettingsPanel{},
+	&protocol.CollapsePanels{},
+	&protocol.GetClipboard{CopyKey: protocol.CopyKeyCut},
+	&protocol.SetClipboard{Sequence: 0x0102030405060708, Paste: true, Text: strings.Repeat("clip ", 1000)},
+	&protocol.SetDisplayPower{On: true},
+	&protocol.RotateDevice{},
+	&protocol.UhidCreate{ID: 2, VendorID: 0x18d1, ProductID: 0x4ee7, Name: "scrcpy keyboard", ReportDesc: []byte{0x05, 0x01, 0x09, 0x06, 0xa1, 0x01, 0xc0}},
+	&protocol.UhidInput{ID: 2, Data: []byte{0x02, 0x00, 0x04, 0, 0, 0, 0, 0}},
+	&protocol.UhidDestroy{ID: 2},
+	&protocol.OpenHardKeyboardSettings{},
+	&protocol.StartApp{Name: "+org.example.app"},
+}
+
+var deviceMessages = []protocol.DeviceMessage{
+	&protocol.Clipboard{Text: strings.Repeat("device ", 3000)},
+	&protocol.AckClipboard{Sequence: 1<<64 - 1},
+	&protocol.UhidOutput{ID: 2, Data: []byte{0x01, 0x02}},
+}
+
+func TestControlMessageRoundTrip(t *testing.T) {
+	seen := make(map[protocol.ControlMessageType]bool)
+
+	for _, msg := range controlMessages {
+		t.Run(msg.Type().String(), func(t *testing.T) {
+			seen[msg.Type()] = true
+
+			data, err := msg.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+
+			if data[0] != byte(msg.Type()) {
+				t.Fatalf("type byte = %d, want %d", data[0], msg.Type())
+			}
+
+			got, err := protocol.UnmarshalControlMessage(data)
+			if err != nil {
+				t.Fatalf("UnmarshalControlMessage: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("UnmarshalControlMessage = %v, want %v", got, msg)
+			}
+
+			r := bytes.NewReader(append(data, 0xff))
+
+			got, err = protocol.ReadControlMessage(r)
+			if err != nil {
+				t.Fatalf("ReadControlMessage: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("ReadControlMessage = %v, want %v", got, msg)
+			}
+
+			if r.Len() != 1 {
+				t.Fatalf("ReadControlMessage left %d bytes, want 1", r.Len())
+			}
+
+			if _, err := protocol.UnmarshalControlMessage(append(data, 0)); !errors.Is(err, protocol.ErrTrailingData) {
+				t.Fatalf("UnmarshalControlMessage with trailing byte: %v, want ErrTrailingData", err)
+			}
+
+			if len(data) > 1 {
+				if _, err := protocol.ReadControlMessage(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Fatalf("ReadControlMessage truncated: %v, want io.ErrUnexpectedEOF", err)
+				}
+			}
+		})
+	}
+
+	for typ := protocol.ControlMessageType(0); typ < 0xff; typ++ {
+		if _, err := protocol.NewControlMessage(typ); err == nil && !seen[typ] {
+			t.Errorf("no round-trip case for %s", typ)
+		}
+	}
+}
+
+func TestDeviceMessageRoundTrip(t *testing.T) {
+	seen := make(map[protocol.DeviceMessageType]bool)
+
+	for _, msg := range deviceMessages {
+		t.Run(msg.Type().String(), func(t *testing.T) {
+			seen[msg.Type()] = true
+
+			data, err := msg.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+
+			got, err := protocol.UnmarshalDeviceMessage(data)
+			if err != nil {
+				t.Fatalf("UnmarshalDeviceMessage: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("UnmarshalDeviceMessage = %v, want %v", got, msg)
+			}
+
+			r := bytes.NewReader(append(data, 0xff))
+
+			got, err = protocol.ReadDeviceMessage(r)
+			if err != nil {
+				t.Fatalf("ReadDeviceMessage: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("ReadDeviceMessage = %v, want %v", got, msg)
+			}
+
+			if r.Len() != 1 {
+				t.Fatalf("ReadDeviceMessage left %d bytes, want 1", r.Len())
+			}
+
+			if _, err := protocol.ReadDeviceMessage(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("ReadDeviceMessage truncated: %v, want io.ErrUnexpectedEOF", err)
+			}
+		})
+	}
+
+	for typ := protocol.DeviceMessageType(0); typ < 0xff; typ++ {
+		if _, err := protocol.NewDeviceMessage(typ); err == nil && !seen[typ] {
+			t.Errorf("no round-trip case for %s", typ)
+		}
+	}
+}
+
+func TestMarshalTooLong(t *testing.T) {
+	tests := []struct {
+		msg     interface{ MarshalBinary() ([]byte, error) }
+		wantErr error
+	}{
+		{&protocol.InjectText{Text: strings.Repeat("a", protocol.MaxTextLength+1)}, protocol.ErrTextTooLong},
+		{&protocol.InjectText{Text: "\xff"}, protocol.ErrTextTooLong},
+		{&protocol.SetClipboard{Text: strings.Repeat("a", protocol.MaxClipboardLength+1)}, protocol.ErrClipboardTooLong},
+		{&protocol.Clipboard{Text: strings.Repeat("a", protocol.MaxDeviceMsgLength)}, protocol.ErrClipboardTooLong},
+		{&protocol.UhidCreate{Name: strings.Repeat("a", protocol.MaxNameLength+1)}, protocol.ErrUhidNameTooLong},
+		{&protocol.UhidCreate{ReportDesc: make([]byte, protocol.MaxUhidDataLength+1)}, protocol.ErrUhidDataTooLong},
+		{&protocol.UhidInput{Data: make([]byte, protocol.MaxUhidDataLength+1)}, protocol.ErrUhidDataTooLong},
+		{&protocol.UhidOutput{Data: make([]byte, protocol.MaxUhidDataLength+1)}, protocol.ErrUhidDataTooLong},
+		{&protocol.StartApp{Name: strings.Repeat("a", protocol.MaxNameLength+1)}, protocol.ErrAppNameTooLong},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.msg.MarshalBinary(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%T.MarshalBinary() = %v, want %v", tt.msg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	if _, err := protocol.ReadControlMessage(bytes.NewReader([]byte{0xfe})); !errors.Is(err, protocol.ErrUnknownMessage) {
+		t.Errorf("ReadControlMessage(unknown) = %v, want ErrUnknownMessage", err)
+	}
+
+	if _, err := protocol.ReadDeviceMessage(bytes.NewReader([]byte{0xfe})); !errors.Is(err, protocol.ErrUnknownMessage) {
+		t.Errorf("ReadDeviceMessage(unknown) = %v, want ErrUnknownMessage", err)
+	}
+
+	if _, err := protocol.ReadControlMessage(bytes.NewReader([]byte{byte(protocol.CtrlInjectText), 0, 0, 0x01, 0x2d})); !errors.Is(err, protocol.ErrProtocol) {
+		t.Errorf("ReadControlMessage(oversized text) = %v, want ErrProtocol", err)
+	}
+
+	if _, err := protocol.ReadDeviceMessage(bytes.NewReader([]byte{byte(protocol.DeviceClipboard), 0xff, 0xff, 0xff, 0xff})); !errors.Is(err, protocol.ErrProtocol) {
+		t.Errorf("ReadDeviceMessage(2^32-1 length) = %v, want ErrProtocol", err)
+	}
+
+	if _, err := protocol.UnmarshalControlMessage(nil); !errors.Is(err, protocol.ErrProtocol) {
+		t.Errorf("UnmarshalControlMessage(nil) = %v, want ErrProtocol", err)
+	}
+}
+
+func FuzzReadControlMessage(f *testing.F) {
+	for _, msg := range controlMessages {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+
+		msg, err := protocol.ReadControlMessage(r)
+		if err != nil {
+			return
+		}
+
+		encoded, err := msg.MarshalBinary()
+		if errors.Is(err, protocol.ErrTextTooLong) || errors.Is(err, protocol.ErrClipboardTooLong) {
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("%v.MarshalBinary: %v", msg, err)
+		}
+
+		if n := len(data) - r.Len(); len(encoded) != n {
+			t.Fatalf("%v: read %d bytes, marshaled %d", msg, n, len(encoded))
+		}
+
+		again, err := protocol.UnmarshalControlMessage(encoded)
+		if err != nil {
+			t.Fatalf("UnmarshalControlMessage(%x): %v", encoded, err)
+		}
+
+		if !reflect.DeepEqual(again, msg) {
+			t.Fatalf("round trip = %v, want %v", again, msg)
+		}
+	})
+}
+
+func FuzzReadDeviceMessage(f *testing.F) {
+	for _, msg := range deviceMessages {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+
+		msg, err := protocol.ReadDeviceMessage(r)
+		if err != nil {
+			return
+		}
+
+		encoded, err := msg.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%v.MarshalBinary: %v", msg, err)
+		}
+
+		if n := len(data) - r.Len(); len(encoded) != n {
+			t.Fatalf("%v: read %d bytes, marshaled %d", msg, n, len(encoded))
+		}
+
+		again, err := protocol.UnmarshalDeviceMessage(encoded)
+		if err != nil {
+			t.Fatalf("UnmarshalDeviceMessage(%x): %v", encoded, err)
+		}
+
+		if !reflect.DeepEqual(again, msg) {
+			t.Fatalf("round trip = %v, want %v", again, msg)
+		}
+	})
+}
@@ -0,0 +1,536 @@
+package protocol
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+type InjectKeycode struct {
+	Action    byte
+	Keycode   uint32
+	Repeat    uint32
+	MetaState uint32
+}
+
+func (*InjectKeycode) Type() ControlMessageType { return CtrlInjectKeycode }
+
+func (m *InjectKeycode) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlInjectKeycode), m.encode)
+}
+
+func (m *InjectKeycode) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlInjectKeycode), CtrlInjectKeycode, m.decode)
+}
+
+func (m *InjectKeycode) String() string {
+	return fmt.Sprintf("InjectKeycode{action=%s keycode=%d repeat=%d meta=0x%x}", actionString(m.Action), m.Keycode, m.Repeat, m.MetaState)
+}
+
+func (m *InjectKeycode) encode(e *encoder) error {
+	e.u8(m.Action)
+	e.u32(m.Keycode)
+	e.u32(m.Repeat)
+	e.u32(m.MetaState)
+
+	return nil
+}
+
+func (m *InjectKeycode) decode(d *decoder) {
+	m.Action = d.u8()
+	m.Keycode = d.u32()
+	m.Repeat = d.u32()
+	m.MetaState = d.u32()
+}
+
+type InjectText struct {
+	Text string
+}
+
+func (*InjectText) Type() ControlMessageType { return CtrlInjectText }
+
+func (m *InjectText) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlInjectText), m.encode)
+}
+
+func (m *InjectText) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlInjectText), CtrlInjectText, m.decode)
+}
+
+func (m *InjectText) String() string {
+	return fmt.Sprintf("InjectText{text=%q}", m.Text)
+}
+
+func (m *InjectText) encode(e *encoder) error {
+	if !utf8.ValidString(m.Text) || len(m.Text) > MaxTextLength {
+		return ErrTextTooLong
+	}
+
+	e.u32(uint32(len(m.Text)))
+	e.bytes([]byte(m.Text))
+
+	return nil
+}
+
+func (m *InjectText) decode(d *decoder) {
+	m.Text = string(d.bytes(d.u32(), MaxTextLength))
+}
+
+type InjectTouchEvent struct {
+	Action       byte
+	PointerID    uint64
+	X            uint32
+	Y            uint32
+	ScreenWidth  uint16
+	ScreenHeight uint16
+	Pressure     uint16
+	ActionButton uint32
+	Buttons      uint32
+}
+
+func (*InjectTouchEvent) Type() ControlMessageType { return CtrlInjectTouchEvent }
+
+func (m *InjectTouchEvent) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlInjectTouchEvent), m.encode)
+}
+
+func (m *InjectTouchEvent) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlInjectTouchEvent), CtrlInjectTouchEvent, m.decode)
+}
+
+func (m *InjectTouchEvent) String() string {
+	return fmt.Sprintf("InjectTouchEvent{action=%s pointer=%d pos=%d,%d screen=%dx%d pressure=%d action_button=0x%x buttons=0x%x}",
+		actionString(m.Action), m.PointerID, m.X, m.Y, m.ScreenWidth, m.ScreenHeight, m.Pressure, m.ActionButton, m.Buttons)
+}
+
+func (m *InjectTouchEvent) encode(e *encoder) error {
+	e.u8(m.Action)
+	e.u64(m.PointerID)
+	e.u32(m.X)
+	e.u32(m.Y)
+	e.u16(m.ScreenWidth)
+	e.u16(m.ScreenHeight)
+	e.u16(m.Pressure)
+	e.u32(m.ActionButton)
+	e.u32(m.Buttons)
+
+	return nil
+}
+
+func (m *InjectTouchEvent) decode(d *decoder) {
+	m.Action = d.u8()
+	m.PointerID = d.u64()
+	m.X = d.u32()
+	m.Y = d.u32()
+	m.ScreenWidth = d.u16()
+	m.ScreenHeight = d.u16()
+	m.Pressure = d.u16()
+	m.ActionButton = d.u32()
+	m.Buttons = d.u32()
+}
+
+type InjectScrollEvent struct {
+	X            int32
+	Y            int32
+	ScreenWidth  uint16
+	ScreenHeight uint16
+	HScroll      int16
+	VScroll      int16
+	Buttons      uint32
+}
+
+func (*InjectScrollEvent) Type() ControlMessageType { return CtrlInjectScrollEvent }
+
+func (m *InjectScrollEvent) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlInjectScrollEvent), m.encode)
+}
+
+func (m *InjectScrollEvent) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlInjectScrollEvent), CtrlInjectScrollEvent, m.decode)
+}
+
+func (m *InjectScrollEvent) String() string {
+	return fmt.Sprintf("InjectScrollEvent{pos=%d,%d screen=%dx%d hscroll=%d vscroll=%d buttons=0x%x}",
+		m.X, m.Y, m.ScreenWidth, m.ScreenHeight, m.HScroll, m.VScroll, m.Buttons)
+}
+
+func (m *InjectScrollEvent) encode(e *encoder) error {
+	e.u32(uint32(m.X))
+	e.u32(uint32(m.Y))
+	e.u16(m.ScreenWidth)
+	e.u16(m.ScreenHeight)
+	e.u16(uint16(m.HScroll))
+	e.u16(uint16(m.VScroll))
+	e.u32(m.Buttons)
+
+	return nil
+}
+
+func (m *InjectScrollEvent) decode(d *decoder) {
+	m.X = int32(d.u32())
+	m.Y = int32(d.u32())
+	m.ScreenWidth = d.u16()
+	m.ScreenHeight = d.u16()
+	m.HScroll = int16(d.u16())
+	m.VScroll = int16(d.u16())
+	m.Buttons = d.u32()
+}
+
+type BackOrScreenOn struct {
+	Action byte
+}
+
+func (*BackOrScreenOn) Type() ControlMessageType { return CtrlBackOrScreenOn }
+
+func (m *BackOrScreenOn) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlBackOrScreenOn), m.encode)
+}
+
+func (m *BackOrScreenOn) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlBackOrScreenOn), CtrlBackOrScreenOn, m.decode)
+}
+
+func (m *BackOrScreenOn) String() string {
+	return fmt.Sprintf("BackOrScreenOn{action=%s}", actionString(m.Action))
+}
+
+func (m *BackOrScreenOn) encode(e *encoder) error {
+	e.u8(m.Action)
+
+	return nil
+}
+
+func (m *BackOrScreenOn) decode(d *decoder) {
+	m.Action = d.u8()
+}
+
+type ExpandNotificationPanel struct{}
+
+func (*ExpandNotificationPanel) Type() ControlMessageType { return CtrlExpandNotificationPanel }
+
+func (m *ExpandNotificationPanel) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlExpandNotificationPanel), m.encode)
+}
+
+func (m *ExpandNotificationPanel) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlExpandNotificationPanel), CtrlExpandNotificationPanel, m.decode)
+}
+
+func (*ExpandNotificationPanel) String() string { return "ExpandNotificationPanel{}" }
+
+func (*ExpandNotificationPanel) encode(*encoder) error { return nil }
+
+func (*ExpandNotificationPanel) decode(*decoder) {}
+
+type ExpandSettingsPanel struct{}
+
+func (*ExpandSettingsPanel) Type() ControlMessageType { return CtrlExpandSettingsPanel }
+
+func (m *ExpandSettingsPanel) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlExpandSettingsPanel), m.encode)
+}
+
+func (m *ExpandSettingsPanel) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlExpandSettingsPanel), CtrlExpandSettingsPanel, m.decode)
+}
+
+func (*ExpandSettingsPanel) String() string { return "ExpandSettingsPanel{}" }
+
+func (*ExpandSettingsPanel) encode(*encoder) error { return nil }
+
+func (*ExpandSettingsPanel) decode(*decoder) {}
+
+type CollapsePanels struct{}
+
+func (*CollapsePanels) Type() ControlMessageType { return CtrlCollapsePanels }
+
+func (m *CollapsePanels) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlCollapsePanels), m.encode)
+}
+
+func (m *CollapsePanels) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlCollapsePanels), CtrlCollapsePanels, m.decode)
+}
+
+func (*CollapsePanels) String() string { return "CollapsePanels{}" }
+
+func (*CollapsePanels) encode(*encoder) error { return nil }
+
+func (*CollapsePanels) decode(*decoder) {}
+
+type GetClipboard struct {
+	CopyKey byte
+}
+
+func (*GetClipboard) Type() ControlMessageType { return CtrlGetClipboard }
+
+func (m *GetClipboard) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlGetClipboard), m.encode)
+}
+
+func (m *GetClipboard) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlGetClipboard), CtrlGetClipboard, m.decode)
+}
+
+func (m *GetClipboard) String() string {
+	return fmt.Sprintf("GetClipboard{copy_key=%s}", copyKeyString(m.CopyKey))
+}
+
+func (m *GetClipboard) encode(e *encoder) error {
+	e.u8(m.CopyKey)
+
+	return nil
+}
+
+func (m *GetClipboard) decode(d *decoder) {
+	m.CopyKey = d.u8()
+}
+
+type SetClipboard struct {
+	Sequence uint64
+	Paste    bool
+	Text     string
+}
+
+func (*SetClipboard) Type() ControlMessageType { return CtrlSetClipboard }
+
+func (m *SetClipboard) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlSetClipboard), m.encode)
+}
+
+func (m *SetClipboard) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlSetClipboard), CtrlSetClipboard, m.decode)
+}
+
+func (m *SetClipboard) String() string {
+	return fmt.Sprintf("SetClipboard{sequence=%d paste=%t text=%q}", m.Sequence, m.Paste, m.Text)
+}
+
+func (m *SetClipboard) encode(e *encoder) error {
+	if !utf8.ValidString(m.Text) || len(m.Text) > MaxClipboardLength {
+		return ErrClipboardTooLong
+	}
+
+	e.u64(m.Sequence)
+	e.bool(m.Paste)
+	e.u32(uint32(len(m.Text)))
+	e.bytes([]byte(m.Text))
+
+	return nil
+}
+
+func (m *SetClipboard) decode(d *decoder) {
+	m.Sequence = d.u64()
+	m.Paste = d.bool()
+	m.Text = string(d.bytes(d.u32(), MaxClipboardLength))
+}
+
+type SetDisplayPower struct {
+	On bool
+}
+
+func (*SetDisplayPower) Type() ControlMessageType { return CtrlSetDisplayPower }
+
+func (m *SetDisplayPower) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlSetDisplayPower), m.encode)
+}
+
+func (m *SetDisplayPower) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlSetDisplayPower), CtrlSetDisplayPower, m.decode)
+}
+
+func (m *SetDisplayPower) String() string {
+	return fmt.Sprintf("SetDisplayPower{on=%t}", m.On)
+}
+
+func (m *SetDisplayPower) encode(e *encoder) error {
+	e.bool(m.On)
+
+	return nil
+}
+
+func (m *SetDisplayPower) decode(d *decoder) {
+	m.On = d.bool()
+}
+
+type RotateDevice struct{}
+
+func (*RotateDevice) Type() ControlMessageType { return CtrlRotateDevice }
+
+func (m *RotateDevice) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlRotateDevice), m.encode)
+}
+
+func (m *RotateDevice) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlRotateDevice), CtrlRotateDevice, m.decode)
+}
+
+func (*RotateDevice) String() string { return "RotateDevice{}" }
+
+func (*RotateDevice) encode(*encoder) error { return nil }
+
+func (*RotateDevice) decode(*decoder) {}
+
+type UhidCreate struct {
+	ID         uint16
+	VendorID   uint16
+	ProductID  uint16
+	Name       string
+	ReportDesc []byte
+}
+
+func (*UhidCreate) Type() ControlMessageType { return CtrlUhidCreate }
+
+func (m *UhidCreate) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlUhidCreate), m.encode)
+}
+
+func (m *UhidCreate) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlUhidCreate), CtrlUhidCreate, m.decode)
+}
+
+func (m *UhidCreate) String() string {
+	return fmt.Sprintf("UhidCreate{id=%d vendor=0x%04x product=0x%04x name=%q report_desc=%d bytes}", m.ID, m.VendorID, m.ProductID, m.Name, len(m.ReportDesc))
+}
+
+func (m *UhidCreate) encode(e *encoder) error {
+	if len(m.Name) > MaxNameLength {
+		return ErrUhidNameTooLong
+	}
+
+	if len(m.ReportDesc) > MaxUhidDataLength {
+		return ErrUhidDataTooLong
+	}
+
+	e.u16(m.ID)
+	e.u16(m.VendorID)
+	e.u16(m.ProductID)
+	e.u8(byte(len(m.Name)))
+	e.bytes([]byte(m.Name))
+	e.u16(uint16(len(m.ReportDesc)))
+	e.bytes(m.ReportDesc)
+
+	return nil
+}
+
+func (m *UhidCreate) decode(d *decoder) {
+	m.ID = d.u16()
+	m.VendorID = d.u16()
+	m.ProductID = d.u16()
+	m.Name = string(d.bytes(uint32(d.u8()), MaxNameLength))
+	m.ReportDesc = d.bytes(uint32(d.u16()), MaxUhidDataLength)
+}
+
+type UhidInput struct {
+	ID   uint16
+	Data []byte
+}
+
+func (*UhidInput) Type() ControlMessageType { return CtrlUhidInput }
+
+func (m *UhidInput) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlUhidInput), m.encode)
+}
+
+func (m *UhidInput) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlUhidInput), CtrlUhidInput, m.decode)
+}
+
+func (m *UhidInput) String() string {
+	return fmt.Sprintf("UhidInput{id=%d data=% x}", m.ID, m.Data)
+}
+
+func (m *UhidInput) encode(e *encoder) error {
+	if len(m.Data) > MaxUhidDataLength {
+		return ErrUhidDataTooLong
+	}
+
+	e.u16(m.ID)
+	e.u16(uint16(len(m.Data)))
+	e.bytes(m.Data)
+
+	return nil
+}
+
+func (m *UhidInput) decode(d *decoder) {
+	m.ID = d.u16()
+	m.Data = d.bytes(uint32(d.u16()), MaxUhidDataLength)
+}
+
+type UhidDestroy struct {
+	ID uint16
+}
+
+func (*UhidDestroy) Type() ControlMessageType { return CtrlUhidDestroy }
+
+func (m *UhidDestroy) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlUhidDestroy), m.encode)
+}
+
+func (m *UhidDestroy) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlUhidDestroy), CtrlUhidDestroy, m.decode)
+}
+
+func (m *UhidDestroy) String() string {
+	return fmt.Sprintf("UhidDestroy{id=%d}", m.ID)
+}
+
+func (m *UhidDestroy) encode(e *encoder) error {
+	e.u16(m.ID)
+
+	return nil
+}
+
+func (m *UhidDestroy) decode(d *decoder) {
+	m.ID = d.u16()
+}
+
+type OpenHardKeyboardSettings struct{}
+
+func (*OpenHardKeyboardSettings) Type() ControlMessageType { return CtrlOpenHardKeyboardSettings }
+
+func (m *OpenHardKeyboardSettings) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlOpenHardKeyboardSettings), m.encode)
+}
+
+func (m *OpenHardKeyboardSettings) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlOpenHardKeyboardSettings), CtrlOpenHardKeyboardSettings, m.decode)
+}
+
+func (*OpenHardKeyboardSettings) String() string { return "OpenHardKeyboardSettings{}" }
+
+func (*OpenHardKeyboardSettings) encode(*encoder) error { return nil }
+
+func (*OpenHardKeyboardSettings) decode(*decoder) {}
+
+type StartApp struct {
+	Name string
+}
+
+func (*StartApp) Type() ControlMessageType { return CtrlStartApp }
+
+func (m *StartApp) MarshalBinary() ([]byte, error) {
+	return marshal(byte(CtrlStartApp), m.encode)
+}
+
+func (m *StartApp) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(CtrlStartApp), CtrlStartApp, m.decode)
+}
+
+func (m *StartApp) String() string {
+	return fmt.Sprintf("StartApp{name=%q}", m.Name)
+}
+
+func (m *StartApp) encode(e *encoder) error {
+	if len(m.Name) > MaxNameLength {
+		return ErrAppNameTooLong
+	}
+
+	e.u8(byte(len(m.Name)))
+	e.bytes([]byte(m.Name))
+
+	return nil
+}
+
+func (m *StartApp) decode(d *decoder) {
+	m.Name = string(d.bytes(uint32(d.u8()), MaxNameLength))
+}
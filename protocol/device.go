@@ -0,0 +1,100 @@
+package protocol
+
+import "fmt"
+
+type Clipboard struct {
+	Text string
+}
+
+func (*Clipboard) Type() DeviceMessageType { return DeviceClipboard }
+
+func (m *Clipboard) MarshalBinary() ([]byte, error) {
+	return marshal(byte(DeviceClipboard), m.encode)
+}
+
+func (m *Clipboard) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(DeviceClipboard), DeviceClipboard, m.decode)
+}
+
+func (m *Clipboard) String() string {
+	return fmt.Sprintf("Clipboard{text=%q}", m.Text)
+}
+
+func (m *Clipboard) encode(e *encoder) error {
+	if len(m.Text) > MaxDeviceMsgLength-5 {
+		return ErrClipboardTooLong
+	}
+
+	e.u32(uint32(len(m.Text)))
+	e.bytes([]byte(m.Text))
+
+	return nil
+}
+
+func (m *Clipboard) decode(d *decoder) {
+	m.Text = string(d.bytes(d.u32(), MaxDeviceMsgLength-5))
+}
+
+type AckClipboard struct {
+	Sequence uint64
+}
+
+func (*AckClipboard) Type() DeviceMessageType { return DeviceAckClipboard }
+
+func (m *AckClipboard) MarshalBinary() ([]byte, error) {
+	return marshal(byte(DeviceAckClipboard), m.encode)
+}
+
+func (m *AckClipboard) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(DeviceAckClipboard), DeviceAckClipboard, m.decode)
+}
+
+func (m *AckClipboard) String() string {
+	return fmt.Sprintf("AckClipboard{sequence=%d}", m.Sequence)
+}
+
+func (m *AckClipboard) encode(e *encoder) error {
+	e.u64(m.Sequence)
+
+	return nil
+}
+
+func (m *AckClipboard) decode(d *decoder) {
+	m.Sequence = d.u64()
+}
+
+type UhidOutput struct {
+	ID   uint16
+	Data []byte
+}
+
+func (*UhidOutput) Type() DeviceMessageType { return DeviceUhidOutput }
+
+func (m *UhidOutput) MarshalBinary() ([]byte, error) {
+	return marshal(byte(DeviceUhidOutput), m.encode)
+}
+
+func (m *UhidOutput) UnmarshalBinary(b []byte) error {
+	return unmarshal(b, byte(DeviceUhidOutput), DeviceUhidOutput, m.decode)
+}
+
+func (m *UhidOutput) String() string {
+	return fmt.Sprintf("UhidOutput{id=%d data=% x}", m.ID, m.Data)
+}
+
+func (m *UhidOutput) encode(e *encoder) error {
+	if len(m.Data) > MaxUhidDataLength {
+		return ErrUhidDataTooLong
+	}
+
+	e.u16(m.ID)
+	e.u16(uint16(len(m.Data)))
+	e.bytes(m.Data)
+
+	return nil
+}
+
+func (m *UhidOutput) decode(d *decoder) {
+	m.ID = d.u16()
+	m.Data = d.bytes(uint32(d.u16()), MaxUhidDataLength)
+}
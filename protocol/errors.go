@@ -0,0 +1,17 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTextTooLong      = errors.New("inject text > 300 bytes")
+	ErrClipboardTooLong = errors.New("clipboard text too long")
+	ErrUhidDataTooLong  = errors.New("uhid data exceeds 64 KiB")
+	ErrAppNameTooLong   = errors.New("start app name exceeds 255 bytes")
+	ErrUhidNameTooLong  = errors.New("uhid name exceeds 255 bytes")
+	ErrProtocol         = errors.New("protocol error")
+	ErrUnknownMessage   = fmt.Errorf("%w: unknown message type", ErrProtocol)
+	ErrTrailingData     = fmt.Errorf("%w: trailing data after message", ErrProtocol)
+)
@@ -0,0 +1,120 @@
+package protocol
+
+import "fmt"
+
+type ControlMessageType byte
+type DeviceMessageType byte
+
+const (
+	CtrlInjectKeycode            ControlMessageType = 0
+	CtrlInjectText               ControlMessageType = 1
+	CtrlInjectTouchEvent         ControlMessageType = 2
+	CtrlInjectScrollEvent        ControlMessageType = 3
+	CtrlBackOrScreenOn           ControlMessageType = 4
+	CtrlExpandNotificationPanel  ControlMessageType = 5
+	CtrlExpandSettingsPanel      ControlMessageType = 6
+	CtrlCollapsePanels           ControlMessageType = 7
+	CtrlGetClipboard             ControlMessageType = 8
+	CtrlSetClipboard             ControlMessageType = 9
+	CtrlSetDisplayPower          ControlMessageType = 10
+	CtrlRotateDevice             ControlMessageType = 11
+	CtrlUhidCreate               ControlMessageType = 12
+	CtrlUhidInput                ControlMessageType = 13
+	CtrlUhidDestroy              ControlMessageType = 14
+	CtrlOpenHardKeyboardSettings ControlMessageType = 15
+	CtrlStartApp                 ControlMessageType = 16
+)
+
+const (
+	DeviceClipboard    DeviceMessageType = 0
+	DeviceAckClipboard DeviceMessageType = 1
+	DeviceUhidOutput   DeviceMessageType = 2
+)
+
+const (
+	ActionDown byte = 0
+	ActionUp   byte = 1
+	ActionMove byte = 2
+)
+
+const (
+	CopyKeyNone byte = 0
+	CopyKeyCopy byte = 1
+	CopyKeyCut  byte = 2
+)
+
+const (
+	MaxTextLength      = 300
+	MaxClipboardLength = 1<<18 - 14
+	MaxDeviceMsgLength = 1 << 18
+	MaxUhidDataLength  = 0xffff
+	MaxNameLength      = 255
+)
+
+var controlTypeNames = [...]string{
+	CtrlInjectKeycode:            "InjectKeycode",
+	CtrlInjectText:               "InjectText",
+	CtrlInjectTouchEvent:         "InjectTouchEvent",
+	CtrlInjectScrollEvent:        "InjectScrollEvent",
+	CtrlBackOrScreenOn:           "BackOrScreenOn",
+	CtrlExpandNotificationPanel:  "ExpandNotificationPanel",
+	CtrlExpandSettingsPanel:      "ExpandSettingsPanel",
+	CtrlCollapsePanels:           "CollapsePanels",
+	CtrlGetClipboard:             "GetClipboard",
+	CtrlSetClipboard:             "SetClipboard",
+	CtrlSetDisplayPower:          "SetDisplayPower",
+	CtrlRotateDevice:             "RotateDevice",
+	CtrlUhidCreate:               "UhidCreate",
+	CtrlUhidInput:                "UhidInput",
+	CtrlUhidDestroy:              "UhidDestroy",
+	CtrlOpenHardKeyboardSettings: "OpenHardKeyboardSettings",
+	CtrlStartApp:                 "StartApp",
+}
+
+var deviceTypeNames = [...]string{
+	DeviceClipboard:    "Clipboard",
+	DeviceAckClipboard: "AckClipboard",
+	DeviceUhidOutput:   "UhidOutput",
+}
+
+func (t ControlMessageType) String() string {
+	if int(t) < len(controlTypeNames) {
+		return controlTypeNames[t]
+	}
+
+	return fmt.Sprintf("ControlMessageType(%d)", byte(t))
+}
+
+func (t DeviceMessageType) String() string {
+	if int(t) < len(deviceTypeNames) {
+		return deviceTypeNames[t]
+	}
+
+	return fmt.Sprintf("DeviceMessageType(%d)", byte(t))
+}
+
+func actionString(action byte) string {
+	switch action {
+	case ActionDown:
+		return "down"
+	case ActionUp:
+		return "up"
+	case ActionMove:
+		return "move"
+	default:
+		return fmt.Sprintf("action(%d)", action)
+	}
+}
+
+func copyKeyString(key byte) string {
+	switch key {
+	case CopyKeyNone:
+		return "none"
+	case CopyKeyCopy:
+		return "copy"
+	case CopyKeyCut:
+		return "cut"
+	default:
+		return fmt.Sprintf("copy_key(%d)", key)
+	}
+}
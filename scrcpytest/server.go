@@ -0,0 +1,409 @@
+package scrcpytest
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+var (
+	ErrNotConnected = errors.New("client not connected")
+	ErrNoStream     = errors.New("stream disabled")
+)
+
+type Config struct {
+	DeviceName     string
+	VideoCodec     scrcpy.Codec
+	AudioCodec     scrcpy.Codec
+	Width          uint32
+	Height         uint32
+	Video          bool
+	Audio          bool
+	Control        bool
+	SendDummyByte  bool
+	SendDeviceMeta bool
+	SendCodecMeta  bool
+	Clipboard      string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		DeviceName:     "scrcpytest",
+		VideoCodec:     scrcpy.CodecH264,
+		AudioCodec:     scrcpy.CodecOpus,
+		Width:          1080,
+		Height:         1920,
+		Video:          true,
+		Control:        true,
+		SendDummyByte:  true,
+		SendDeviceMeta: true,
+		SendCodecMeta:  true,
+	}
+}
+
+type Server struct {
+	cfg       Config
+	ln        net.Listener
+	connected chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	acceptErr error
+
+	video   *stream
+	audio   *stream
+	control *stream
+
+	mutex     sync.Mutex
+	messages  []protocol.ControlMessage
+	changed   chan struct{}
+	clipboard string
+	onMessage func(protocol.ControlMessage)
+}
+
+type stream struct {
+	mutex sync.Mutex
+	conn  net.Conn
+}
+
+func NewServer(cfg Config) (*Server, error) {
+	if !cfg.Video && !cfg.Audio && !cfg.Control {
+		return nil, scrcpy.ErrNoSockets
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	s := &Server{
+		cfg:       cfg,
+		ln:        ln,
+		connected: make(chan struct{}),
+		done:      make(chan struct{}),
+		changed:   make(chan struct{}),
+		clipboard: cfg.Clipboard,
+	}
+
+	s.wg.Add(1)
+
+	go s.accept()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string { return s.ln.Addr().String() }
+
+func (s *Server) DialOptions() []scrcpy.DialOption {
+	return []scrcpy.DialOption{
+		scrcpy.WithVideo(s.cfg.Video),
+		scrcpy.WithAudio(s.cfg.Audio),
+		scrcpy.WithControl(s.cfg.Control),
+		scrcpy.WithDummyByte(s.cfg.SendDummyByte),
+		scrcpy.WithDeviceMeta(s.cfg.SendDeviceMeta),
+		scrcpy.WithCodecMeta(s.cfg.SendCodecMeta),
+	}
+}
+
+func (s *Server) Dial(ctx context.Context, opts ...scrcpy.DialOption) (*scrcpy.Client, error) {
+	return scrcpy.Dial(ctx, s.Addr(), append(s.DialOptions(), opts...)...)
+}
+
+func (s *Server) WaitConnected(ctx context.Context) error {
+	select {
+	case <-s.connected:
+		return s.acceptErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) OnMessage(fn func(protocol.ControlMessage)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.onMessage = fn
+}
+
+func (s *Server) Messages() []protocol.ControlMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]protocol.ControlMessage(nil), s.messages...)
+}
+
+func (s *Server) WaitMessages(ctx context.Context, n int) ([]protocol.ControlMessage, error) {
+	for {
+		s.mutex.Lock()
+		msgs := append([]protocol.ControlMessage(nil), s.messages...)
+		changed := s.changed
+		s.mutex.Unlock()
+
+		if len(msgs) >= n {
+			return msgs, nil
+		}
+
+		select {
+		case <-changed:
+		case <-s.done:
+			return msgs, net.ErrClosed
+		case <-ctx.Done():
+			return msgs, ctx.Err()
+		}
+	}
+}
+
+func (s *Server) Clipboard() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.clipboard
+}
+
+func (s *Server) SendVideo(pkt scrcpy.Packet) error {
+	return s.sendPacket(s.video, pkt)
+}
+
+func (s *Server) SendAudio(pkt scrcpy.Packet) error {
+	return s.sendPacket(s.audio, pkt)
+}
+
+func (s *Server) StreamVideo(ctx context.Context, pkts []scrcpy.Packet) error {
+	return s.streamPackets(ctx, s.video, pkts)
+}
+
+func (s *Server) StreamAudio(ctx context.Context, pkts []scrcpy.Packet) error {
+	return s.streamPackets(ctx, s.audio, pkts)
+}
+
+func (s *Server) SendDeviceMessage(msg protocol.DeviceMessage) error {
+	buf, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return s.write(s.control, buf)
+}
+
+func (s *Server) Close() error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+
+	err := s.ln.Close()
+
+	<-s.connected
+
+	for _, st := range []*stream{s.video, s.audio, s.control} {
+		if st != nil {
+			_ = st.conn.Close()
+		}
+	}
+
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	defer close(s.connected)
+
+	first := true
+
+	for _, st := range []struct {
+		enabled bool
+		dst     **stream
+	}{
+		{s.cfg.Video, &s.video},
+		{s.cfg.Audio, &s.audio},
+		{s.cfg.Control, &s.control},
+	} {
+		if !st.enabled {
+			continue
+		}
+
+		conn, err := s.ln.Accept()
+		if err != nil {
+			s.acceptErr = fmt.Errorf("accept: %w", err)
+
+			return
+		}
+
+		*st.dst = &stream{conn: conn}
+
+		if first && s.cfg.SendDummyByte {
+			if err := (*st.dst).write([]byte{0}); err != nil {
+				s.acceptErr = fmt.Errorf("write dummy byte: %w", err)
+
+				return
+			}
+		}
+
+		first = false
+	}
+
+	if err := s.writeHandshake(); err != nil {
+		s.acceptErr = err
+
+		return
+	}
+
+	if s.control != nil {
+		s.wg.Add(1)
+
+		go s.readControl()
+	}
+}
+
+func (s *Server) writeHandshake() error {
+	first := s.video
+	if first == nil {
+		first = s.audio
+	}
+
+	if first == nil {
+		first = s.control
+	}
+
+	if s.cfg.SendDeviceMeta {
+		name := make([]byte, 64)
+		copy(name[:63], s.cfg.DeviceName)
+
+		if err := first.write(name); err != nil {
+			return fmt.Errorf("write device meta: %w", err)
+		}
+	}
+
+	if !s.cfg.SendCodecMeta {
+		return nil
+	}
+
+	if s.video != nil {
+		meta := binary.BigEndian.AppendUint32(nil, uint32(s.cfg.VideoCodec))
+		meta = binary.BigEndian.AppendUint32(meta, s.cfg.Width)
+		meta = binary.BigEndian.AppendUint32(meta, s.cfg.Height)
+
+		if err := s.video.write(meta); err != nil {
+			return fmt.Errorf("write video meta: %w", err)
+		}
+	}
+
+	if s.audio != nil {
+		if err := s.audio.write(binary.BigEndian.AppendUint32(nil, uint32(s.cfg.AudioCodec))); err != nil {
+			return fmt.Errorf("write audio meta: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) readControl() {
+	defer s.wg.Done()
+
+	r := bufio.NewReader(s.control.conn)
+
+	for {
+		msg, err := protocol.ReadControlMessage(r)
+		if err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.messages = append(s.messages, msg)
+		close(s.changed)
+		s.changed = make(chan struct{})
+		fn := s.onMessage
+		s.mutex.Unlock()
+
+		s.reply(msg)
+
+		if fn != nil {
+			fn(msg)
+		}
+	}
+}
+
+func (s *Server) reply(msg protocol.ControlMessage) {
+	switch m := msg.(type) {
+	case *protocol.GetClipboard:
+		_ = s.SendDeviceMessage(&protocol.Clipboard{Text: s.Clipboard()})
+	case *protocol.SetClipboard:
+		s.mutex.Lock()
+		s.clipboard = m.Text
+		s.mutex.Unlock()
+
+		if m.Sequence != 0 {
+			_ = s.SendDeviceMessage(&protocol.AckClipboard{Sequence: m.Sequence})
+		}
+	}
+}
+
+func (s *Server) streamPackets(ctx context.Context, st *stream, pkts []scrcpy.Packet) error {
+	for _, pkt := range pkts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.sendPacket(st, pkt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) sendPacket(st *stream, pkt scrcpy.Packet) error {
+	ptsFlags := pkt.PTS
+
+	if pkt.IsConfig {
+		ptsFlags |= 1 << 63
+	}
+
+	if pkt.IsKeyFrame {
+		ptsFlags |= 1 << 62
+	}
+
+	buf := binary.BigEndian.AppendUint64(nil, ptsFlags)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(pkt.Data)))
+	buf = append(buf, pkt.Data...)
+
+	return s.write(st, buf)
+}
+
+func (s *Server) write(st *stream, buf []byte) error {
+	select {
+	case <-s.connected:
+	default:
+		return ErrNotConnected
+	}
+
+	if s.acceptErr != nil {
+		return s.acceptErr
+	}
+
+	if st == nil {
+		return ErrNoStream
+	}
+
+	return st.write(buf)
+}
+
+func (st *stream) write(buf []byte) error {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if _, err := st.conn.Write(buf); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package scrcpy
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	ServerVersion    = "3.3.1"
+	ServerRemotePath = "/data/local/tmp/scrcpy-server.jar"
+	serverMainClass  = "com.genymobile.scrcpy.Server"
+)
+
+const (
+	LogLevelVerbose = "verbose"
+	LogLevelDebug   = "debug"
+	LogLevelInfo    = "info"
+	LogLevelWarn    = "warn"
+	LogLevelError   = "error"
+)
+
+type Crop struct {
+	Width  uint32
+	Height uint32
+	X      uint32
+	Y      uint32
+}
+
+type ServerOptions struct {
+	SCID              int32
+	LogLevel          string
+	TunnelForward     bool
+	Video             bool
+	Audio             bool
+	Control           bool
+	MaxSize           uint16
+	VideoBitRate      uint32
+	AudioBitRate      uint32
+	MaxFPS            float32
+	VideoCodec        Codec
+	AudioCodec        Codec
+	VideoEncoder      string
+	AudioEncoder      string
+	Crop              Crop
+	DisplayID         uint32
+	StayAwake         bool
+	ShowTouches       bool
+	PowerOffOnClose   bool
+	PowerOn           bool
+	ClipboardAutosync bool
+}
+
+func DefaultServerOptions() ServerOptions {
+	return ServerOptions{
+		SCID:              NoSCID,
+		LogLevel:          LogLevelInfo,
+		TunnelForward:     true,
+		Video:             true,
+		Control:           true,
+		PowerOn:           true,
+		ClipboardAutosync: true,
+	}
+}
+
+func (o *ServerOptions) Validate() error {
+	switch o.LogLevel {
+	case "", LogLevelVerbose, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("%w: log level %q", ErrInvalidOption, o.LogLevel)
+	}
+
+	if o.SCID < NoSCID {
+		return fmt.Errorf("%w: scid %d", ErrInvalidOption, o.SCID)
+	}
+
+	if !o.Video && !o.Audio && !o.Control {
+		return ErrNoSockets
+	}
+
+	if o.VideoCodec != CodecDisabled && !o.VideoCodec.IsVideo() {
+		return fmt.Errorf("%w: video codec %s", ErrInvalidOption, o.VideoCodec)
+	}
+
+	if o.AudioCodec != CodecDisabled && !o.AudioCodec.IsAudio() {
+		return fmt.Errorf("%w: audio codec %s", ErrInvalidOption, o.AudioCodec)
+	}
+
+	if o.MaxFPS < 0 {
+		return fmt.Errorf("%w: max fps %v", ErrInvalidOption, o.MaxFPS)
+	}
+
+	if o.Crop != (Crop{}) && (o.Crop.Width == 0 || o.Crop.Height == 0) {
+		return fmt.Errorf("%w: crop %dx%d", ErrInvalidOption, o.Crop.Width, o.Crop.Height)
+	}
+
+	return nil
+}
+
+func (o *ServerOptions) Args() ([]string, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	args := []string{ServerVersion}
+
+	if o.SCID != NoSCID {
+		args = append(args, fmt.Sprintf("scid=%08x", o.SCID))
+	}
+
+	if o.LogLevel != "" {
+		args = append(args, "log_level="+o.LogLevel)
+	}
+
+	args = append(args,
+		"tunnel_forward="+strconv.FormatBool(o.TunnelForward),
+		"video="+strconv.FormatBool(o.Video),
+		"audio="+strconv.FormatBool(o.Audio),
+		"control="+strconv.FormatBool(o.Control),
+	)
+
+	if o.MaxSize != 0 {
+		args = append(args, fmt.Sprintf("max_size=%d", o.MaxSize))
+	}
+
+	if o.VideoBitRate != 0 {
+		args = append(args, fmt.Sprintf("video_bit_rate=%d", o.VideoBitRate))
+	}
+
+	if o.AudioBitRate != 0 {
+		args = append(args, fmt.Sprintf("audio_bit_rate=%d", o.AudioBitRate))
+	}
+
+	if o.MaxFPS != 0 {
+		args = append(args, "max_fps="+strconv.FormatFloat(float64(o.MaxFPS), 'f', -1, 32))
+	}
+
+	if o.VideoCodec != CodecDisabled {
+		args = append(args, "video_codec="+o.VideoCodec.String())
+	}
+
+	if o.AudioCodec != CodecDisabled {
+		args = append(args, "audio_codec="+o.AudioCodec.String())
+	}
+
+	if o.VideoEncoder != "" {
+		args = append(args, "video_encoder="+o.VideoEncoder)
+	}
+
+	if o.AudioEncoder != "" {
+		args = append(args, "audio_encoder="+o.AudioEncoder)
+	}
+
+	if o.Crop != (Crop{}) {
+		args = append(args, fmt.Sprintf("crop=%d:%d:%d:%d", o.Crop.Width, o.Crop.Height, o.Crop.X, o.Crop.Y))
+	}
+
+	if o.DisplayID != 0 {
+		args = append(args, fmt.Sprintf("display_id=%d", o.DisplayID))
+	}
+
+	args = append(args,
+		"stay_awake="+strconv.FormatBool(o.StayAwake),
+		"show_touches="+strconv.FormatBool(o.ShowTouches),
+		"power_off_on_close="+strconv.FormatBool(o.PowerOffOnClose),
+		"power_on="+strconv.FormatBool(o.PowerOn),
+		"clipboard_autosync="+strconv.FormatBool(o.ClipboardAutosync),
+	)
+
+	return args, nil
+}
+
+func (o *ServerOptions) Command(remotePath string) (string, error) {
+	args, err := o.Args()
+	if err != nil {
+		return "", err
+	}
+
+	cmd := "CLASSPATH=" + remotePath + " app_process / " + serverMainClass
+
+	for _, arg := range args {
+		cmd += " " + arg
+	}
+
+	return cmd, nil
+}
+
+func (o *ServerOptions) DialOptions() DialOptions {
+	d := DefaultDialOptions()
+	d.Video = o.Video
+	d.Audio = o.Audio
+	d.Control = o.Control
+	d.SCID = o.SCID
+	d.SendDummyByte = o.TunnelForward
+
+	return d
+}
+
+func (o *ServerOptions) VideoSize(deviceWidth, deviceHeight uint32) (uint32, uint32) {
+	return VideoSize(deviceWidth, deviceHeight, o.Crop, o.MaxSize)
+}
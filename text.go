@@ -0,0 +1,228 @@
+package scrcpy
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/merzzzl/scrcpy-go/protocol"
+)
+
+type TypeStrategy uint8
+
+const (
+	TypePreferText TypeStrategy = iota
+	TypePreferKeys
+	TypePreferPaste
+)
+
+type TypeOptions struct {
+	Strategy   TypeStrategy
+	Delay      time.Duration
+	Injectable func(r rune) bool
+}
+
+type textKind uint8
+
+const (
+	textKindText textKind = iota
+	textKindKey
+	textKindPaste
+)
+
+type runeKey struct {
+	code  uint32
+	shift bool
+}
+
+var runeKeys = map[rune]runeKey{
+	'\n': {AndroidKeyEnter, false},
+	'\r': {AndroidKeyEnter, false},
+	'\t': {AndroidKeyTab, false},
+	' ':  {AndroidKeySpace, false},
+	',':  {AndroidKeyComma, false},
+	'.':  {AndroidKeyPeriod, false},
+	'`':  {AndroidKeyGrave, false},
+	'-':  {AndroidKeyMinus, false},
+	'=':  {AndroidKeyEquals, false},
+	'[':  {AndroidKeyLeftBracket, false},
+	']':  {AndroidKeyRightBracket, false},
+	'\\': {AndroidKeyBackslash, false},
+	';':  {AndroidKeySemicolon, false},
+	'\'': {AndroidKeyApostrophe, false},
+	'/':  {AndroidKeySlash, false},
+	'@':  {AndroidKeyAt, false},
+	'*':  {AndroidKeyStar, false},
+	'#':  {AndroidKeyPound, false},
+	'+':  {AndroidKeyPlus, false},
+	'~':  {AndroidKeyGrave, true},
+	'!':  {AndroidKey1, true},
+	'$':  {AndroidKey4, true},
+	'%':  {AndroidKey5, true},
+	'^':  {AndroidKey6, true},
+	'&':  {AndroidKey7, true},
+	'(':  {AndroidKey9, true},
+	')':  {AndroidKey0, true},
+	'_':  {AndroidKeyMinus, true},
+	'{':  {AndroidKeyLeftBracket, true},
+	'}':  {AndroidKeyRightBracket, true},
+	'|':  {AndroidKeyBackslash, true},
+	':':  {AndroidKeySemicolon, true},
+	'"':  {AndroidKeyApostrophe, true},
+	'<':  {AndroidKeyComma, true},
+	'>':  {AndroidKeyPeriod, true},
+	'?':  {AndroidKeySlash, true},
+}
+
+func init() {
+	for r := 'a'; r <= 'z'; r++ {
+		runeKeys[r] = runeKey{code: AndroidKeyA + uint32(r-'a')}
+		runeKeys[r-'a'+'A'] = runeKey{code: AndroidKeyA + uint32(r-'a'), shift: true}
+	}
+
+	for r := '0'; r <= '9'; r++ {
+		runeKeys[r] = runeKey{code: AndroidKey0 + uint32(r-'0')}
+	}
+}
+
+func RuneKeycode(r rune) (keycode, meta uint32, ok bool) {
+	k, ok := runeKeys[r]
+	if !ok {
+		return AndroidKeyUnknown, AndroidMetaNone, false
+	}
+
+	if k.shift {
+		meta = AndroidMetaShiftOn | AndroidMetaShiftLeftOn
+	}
+
+	return k.code, meta, true
+}
+
+func IsInjectable(r rune) bool {
+	return r >= 0x20 && r < 0x7f
+}
+
+func (c *Client) TypeText(ctx context.Context, text string, opts TypeOptions) error {
+	if !utf8.ValidString(text) {
+		return ErrInvalidUTF8
+	}
+
+	if opts.Injectable == nil {
+		opts.Injectable = IsInjectable
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	for len(text) > 0 {
+		kind := opts.kind(firstRune(text))
+		n := 0
+
+		for n < len(text) {
+			r, size := utf8.DecodeRuneInString(text[n:])
+			if opts.kind(r) != kind {
+				break
+			}
+
+			n += size
+		}
+
+		if err := c.typeRun(ctx, kind, text[:n], opts.Delay); err != nil {
+			return err
+		}
+
+		text = text[n:]
+	}
+
+	return nil
+}
+
+func (o *TypeOptions) kind(r rune) textKind {
+	if o.Strategy == TypePreferPaste {
+		return textKindPaste
+	}
+
+	if k, ok := runeKeys[r]; ok && (o.Strategy == TypePreferKeys || k.code == AndroidKeyEnter || k.code == AndroidKeyTab) {
+		return textKindKey
+	}
+
+	if o.Injectable(r) {
+		return textKindText
+	}
+
+	return textKindPaste
+}
+
+func (c *Client) typeRun(ctx context.Context, kind textKind, text string, delay time.Duration) error {
+	switch kind {
+	case textKindKey:
+		for _, r := range text {
+			code, meta, _ := RuneKeycode(r)
+
+			if err := c.PressKeyCtx(ctx, code, meta); err != nil {
+				return err
+			}
+
+			if err := sleepCtx(ctx, delay); err != nil {
+				return err
+			}
+		}
+	case textKindText:
+		for _, chunk := range splitUTF8(text, protocol.MaxTextLength) {
+			if err := c.InjectTextCtx(ctx, chunk); err != nil {
+				return err
+			}
+
+			if err := sleepCtx(ctx, delay); err != nil {
+				return err
+			}
+		}
+	case textKindPaste:
+		for _, chunk := range splitUTF8(text, protocol.MaxClipboardLength) {
+			if err := c.SetClipboardAndWait(ctx, chunk, true); err != nil {
+				return err
+			}
+
+			if err := sleepCtx(ctx, delay); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func splitUTF8(s string, size int) []string {
+	var chunks []string
+
+	for len(s) > size {
+		n := size
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+
+		chunks = append(chunks, s[:n])
+		s = s[n:]
+	}
+
+	return append(chunks, s)
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+
+	return r
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
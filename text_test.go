@@ -0,0 +1,145 @@
+package scrcpy_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func describe(msg protocol.ControlMessage) string {
+	switch m := msg.(type) {
+	case *protocol.InjectText:
+		return "text:" + m.Text
+	case *protocol.InjectKeycode:
+		return fmt.Sprintf("key:%d:%d:%d", m.Action, m.Keycode, m.MetaState)
+	case *protocol.SetClipboard:
+		return fmt.Sprintf("paste:%t:%s", m.Paste, m.Text)
+	default:
+		return msg.String()
+	}
+}
+
+func press(code, meta uint32) []string {
+	return []string{
+		fmt.Sprintf("key:%d:%d:%d", scrcpy.ActionDown, code, meta),
+		fmt.Sprintf("key:%d:%d:%d", scrcpy.ActionUp, code, meta),
+	}
+}
+
+func typeText(t *testing.T, text string, opts scrcpy.TypeOptions, n int) []string {
+	t.Helper()
+
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	serveTest(t, c)
+
+	ctx := testContext(t)
+
+	if err := c.TypeText(ctx, text, opts); err != nil {
+		t.Fatalf("TypeText: %v", err)
+	}
+
+	msgs, err := srv.WaitMessages(ctx, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		got = append(got, describe(msg))
+	}
+
+	return got
+}
+
+func TestTypeTextChunks(t *testing.T) {
+	ascii := strings.Repeat("a", 2*protocol.MaxTextLength+100)
+	got := typeText(t, ascii, scrcpy.TypeOptions{}, 3)
+
+	if want := []string{
+		"text:" + ascii[:protocol.MaxTextLength],
+		"text:" + ascii[protocol.MaxTextLength:2*protocol.MaxTextLength],
+		"text:" + ascii[2*protocol.MaxTextLength:],
+	}; !slices.Equal(got, want) {
+		t.Fatalf("chunks = %d messages, want %d", len(got), len(want))
+	}
+
+	accented := strings.Repeat("é", protocol.MaxTextLength)
+	got = typeText(t, accented, scrcpy.TypeOptions{Injectable: func(rune) bool { return true }}, 2)
+
+	var joined string
+
+	for _, m := range got {
+		chunk := strings.TrimPrefix(m, "text:")
+
+		if len(chunk) > protocol.MaxTextLength || !utf8.ValidString(chunk) {
+			t.Fatalf("chunk of %d bytes, valid %t", len(chunk), utf8.ValidString(chunk))
+		}
+
+		joined += chunk
+	}
+
+	if joined != accented {
+		t.Fatal("chunks do not reassemble the text")
+	}
+}
+
+func TestTypeTextKeys(t *testing.T) {
+	shift := uint32(scrcpy.AndroidMetaShiftOn | scrcpy.AndroidMetaShiftLeftOn)
+
+	got := typeText(t, "ab\r\ncd\t", scrcpy.TypeOptions{}, 6)
+	want := slices.Concat(
+		[]string{"text:ab"},
+		press(scrcpy.AndroidKeyEnter, 0),
+		[]string{"text:cd"},
+		press(scrcpy.AndroidKeyTab, 0),
+	)
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+
+	got = typeText(t, "aZ?", scrcpy.TypeOptions{Strategy: scrcpy.TypePreferKeys}, 6)
+	want = slices.Concat(
+		press(scrcpy.AndroidKeyA, 0),
+		press(scrcpy.AndroidKeyZ, shift),
+		press(scrcpy.AndroidKeySlash, shift),
+	)
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestTypeTextPasteFallback(t *testing.T) {
+	got := typeText(t, "hi 日本 ok", scrcpy.TypeOptions{}, 3)
+	want := []string{"text:hi ", "paste:true:日本", "text: ok"}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+
+	got = typeText(t, "abc", scrcpy.TypeOptions{Strategy: scrcpy.TypePreferPaste}, 1)
+
+	if want := []string{"paste:true:abc"}; !slices.Equal(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestTypeTextInvalidUTF8(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	if err := c.TypeText(testContext(t), "ok\xffbad", scrcpy.TypeOptions{}); !errors.Is(err, scrcpy.ErrInvalidUTF8) {
+		t.Fatalf("TypeText = %v, want ErrInvalidUTF8", err)
+	}
+
+	if msgs := srv.Messages(); len(msgs) != 0 {
+		t.Fatalf("sent %d messages for invalid text", len(msgs))
+	}
+}
@@ -0,0 +1,196 @@
+package scrcpy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type TouchSession struct {
+	mutex  sync.Mutex
+	client *Client
+	active map[uint64]touchPointer
+	closed bool
+	stop   func() bool
+}
+
+type touchPointer struct {
+	x       uint32
+	y       uint32
+	buttons uint32
+}
+
+func (c *Client) NewTouchSession() *TouchSession {
+	return c.NewTouchSessionCtx(context.Background())
+}
+
+func (c *Client) NewTouchSessionCtx(ctx context.Context) *TouchSession {
+	s := &TouchSession{
+		client: c,
+		active: make(map[uint64]touchPointer),
+	}
+
+	c.touchMutex.Lock()
+	c.touches = append(c.touches, s)
+	c.touchMutex.Unlock()
+
+	s.stop = context.AfterFunc(ctx, func() { _ = s.Close() })
+
+	return s
+}
+
+func (s *TouchSession) Alloc() uint64 {
+	return s.client.pointerSeq.Add(1)
+}
+
+func (s *TouchSession) Active() []uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make([]uint64, 0, len(s.active))
+	for id := range s.active {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+func (s *TouchSession) IsActive(pointerID uint64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.active[pointerID]
+
+	return ok
+}
+
+func (s *TouchSession) InjectTouch(action byte, pointerID uint64, x, y uint32, pressure uint16, actionButton, buttons uint32) error {
+	return s.InjectTouchCtx(context.Background(), action, pointerID, x, y, pressure, actionButton, buttons)
+}
+
+func (s *TouchSession) InjectTouchCtx(ctx context.Context, action byte, pointerID uint64, x, y uint32, pressure uint16, actionButton, buttons uint32) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	_, down := s.active[pointerID]
+
+	switch {
+	case action == ActionDown && down:
+		return fmt.Errorf("%w: %d", ErrPointerActive, pointerID)
+	case action != ActionDown && !down:
+		return fmt.Errorf("%w: %d", ErrPointerInactive, pointerID)
+	}
+
+	if err := s.client.InjectTouchCtx(ctx, action, pointerID, x, y, pressure, actionButton, buttons); err != nil {
+		return err
+	}
+
+	if action == ActionUp {
+		delete(s.active, pointerID)
+	} else {
+		s.active[pointerID] = touchPointer{x: x, y: y, buttons: buttons}
+	}
+
+	return nil
+}
+
+func (s *TouchSession) Release(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.release(ctx)
+}
+
+func (s *TouchSession) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	s.stop()
+	s.client.removeTouchSession(s)
+
+	ctx, cancel := s.client.releaseContext()
+	defer cancel()
+
+	return s.release(ctx)
+}
+
+func (s *TouchSession) release(ctx context.Context) error {
+	var errs []error
+
+	ids := make([]uint64, 0, len(s.active))
+	for id := range s.active {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		p := s.active[id]
+		delete(s.active, id)
+
+		err := s.client.InjectTouchCtx(ctx, ActionUp, id, p.x, p.y, 0, p.buttons&-p.buttons, 0)
+		if err != nil && !errors.Is(err, ErrClosed) {
+			errs = append(errs, fmt.Errorf("release pointer %d: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) releaseTouches() error {
+	c.touchMutex.Lock()
+	touches := c.touches
+	c.touches = nil
+	c.touchMutex.Unlock()
+
+	var errs []error
+
+	for _, s := range touches {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) releaseActiveTouches() {
+	c.touchMutex.Lock()
+	touches := slices.Clone(c.touches)
+	c.touchMutex.Unlock()
+
+	ctx, cancel := c.releaseContext()
+	defer cancel()
+
+	for _, s := range touches {
+		_ = s.Release(ctx)
+	}
+}
+
+func (c *Client) removeTouchSession(s *TouchSession) {
+	c.touchMutex.Lock()
+	defer c.touchMutex.Unlock()
+
+	c.touches = slices.DeleteFunc(c.touches, func(t *TouchSession) bool { return t == s })
+}
+
+func (c *Client) releaseContext() (context.Context, context.CancelFunc) {
+	if c.opts.WriteTimeout > 0 {
+		return context.WithTimeout(context.Background(), c.opts.WriteTimeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
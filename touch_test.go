@@ -0,0 +1,176 @@
+package scrcpy_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func touchEvents(t *testing.T, srv *scrcpytest.Server, n int) []*protocol.InjectTouchEvent {
+	t.Helper()
+
+	msgs, err := srv.WaitMessages(testContext(t), n)
+	if err != nil {
+		t.Fatalf("WaitMessages: %v", err)
+	}
+
+	events := make([]*protocol.InjectTouchEvent, 0, len(msgs))
+
+	for _, msg := range msgs {
+		ev, ok := msg.(*protocol.InjectTouchEvent)
+		if !ok {
+			t.Fatalf("unexpected message %v", msg)
+		}
+
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+func TestTouchSession(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	ctx := testContext(t)
+
+	s := c.NewTouchSession()
+	a, b := s.Alloc(), s.Alloc()
+
+	if a == b {
+		t.Fatalf("Alloc returned %d twice", a)
+	}
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionMove, a, 1, 1, 0xffff, 0, 0); !errors.Is(err, scrcpy.ErrPointerInactive) {
+		t.Fatalf("move before down = %v, want ErrPointerInactive", err)
+	}
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, a, 10, 20, 0xffff, scrcpy.ButtonPrimary, scrcpy.ButtonPrimary); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, a, 10, 20, 0xffff, 0, 0); !errors.Is(err, scrcpy.ErrPointerActive) {
+		t.Fatalf("second down = %v, want ErrPointerActive", err)
+	}
+
+	buttons := uint32(scrcpy.ButtonSecondary | scrcpy.ButtonTertiary)
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, b, 30, 40, 0xffff, scrcpy.ButtonSecondary, buttons); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionMove, a, 11, 21, 0xffff, 0, scrcpy.ButtonPrimary); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Active(); !slices.Equal(got, []uint64{a, b}) {
+		t.Fatalf("Active = %v, want [%d %d]", got, a, b)
+	}
+
+	if err := s.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	if s.IsActive(a) || s.IsActive(b) {
+		t.Fatal("pointers still active after Release")
+	}
+
+	events := touchEvents(t, srv, 5)
+
+	for i, want := range []protocol.InjectTouchEvent{
+		{Action: scrcpy.ActionUp, PointerID: a, X: 11, Y: 21, ActionButton: scrcpy.ButtonPrimary},
+		{Action: scrcpy.ActionUp, PointerID: b, X: 30, Y: 40, ActionButton: scrcpy.ButtonSecondary},
+	} {
+		got := *events[3+i]
+		got.ScreenWidth, got.ScreenHeight = 0, 0
+
+		if got != want {
+			t.Errorf("release %d = %v, want %v", i, &got, &want)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, a, 0, 0, 0, 0, 0); !errors.Is(err, scrcpy.ErrClosed) {
+		t.Fatalf("inject after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestTouchSessionContext(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	ctx, cancel := context.WithCancel(testContext(t))
+	s := c.NewTouchSessionCtx(ctx)
+	id := s.Alloc()
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, id, 5, 6, 0xffff, scrcpy.ButtonPrimary, scrcpy.ButtonPrimary); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	events := touchEvents(t, srv, 2)
+	if up := events[1]; up.Action != scrcpy.ActionUp || up.PointerID != id || up.X != 5 || up.Y != 6 {
+		t.Fatalf("release = %v", up)
+	}
+
+	if err := s.InjectTouchCtx(testContext(t), scrcpy.ActionDown, id, 0, 0, 0, 0, 0); !errors.Is(err, scrcpy.ErrClosed) {
+		t.Fatalf("inject after cancel = %v, want ErrClosed", err)
+	}
+}
+
+func TestTouchSessionServeShutdown(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	ctx := testContext(t)
+
+	serveCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+
+	go func() { done <- c.Serve(serveCtx) }()
+
+	s := c.NewTouchSession()
+	id := s.Alloc()
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, id, 7, 8, 0xffff, scrcpy.ButtonPrimary, scrcpy.ButtonPrimary); err != nil {
+		t.Fatal(err)
+	}
+
+	stop()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	if up := touchEvents(t, srv, 2)[1]; up.Action != scrcpy.ActionUp || up.PointerID != id {
+		t.Fatalf("release = %v", up)
+	}
+
+	if s.IsActive(id) {
+		t.Fatal("pointer still active after Serve returned")
+	}
+}
+
+func TestClientCloseReleasesTouches(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+	ctx := testContext(t)
+
+	s := c.NewTouchSession()
+	id := s.Alloc()
+
+	if err := s.InjectTouchCtx(ctx, scrcpy.ActionDown, id, 1, 2, 0xffff, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if up := touchEvents(t, srv, 2)[1]; up.Action != scrcpy.ActionUp || up.PointerID != id {
+		t.Fatalf("release = %v", up)
+	}
+}
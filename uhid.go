@@ -0,0 +1,100 @@
+package scrcpy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type uhidDevice struct {
+	output     func(data []byte)
+	markClosed func()
+}
+
+func (c *Client) registerUhid(id uint16, dev *uhidDevice) error {
+	c.uhidMutex.Lock()
+	defer c.uhidMutex.Unlock()
+
+	if _, ok := c.uhidDevices[id]; ok {
+		return fmt.Errorf("%w: %d", ErrUhidIDInUse, id)
+	}
+
+	if c.uhidDevices == nil {
+		c.uhidDevices = make(map[uint16]*uhidDevice)
+	}
+
+	c.uhidDevices[id] = dev
+
+	return nil
+}
+
+func (c *Client) unregisterUhid(id uint16) bool {
+	c.uhidMutex.Lock()
+	defer c.uhidMutex.Unlock()
+
+	_, ok := c.uhidDevices[id]
+	delete(c.uhidDevices, id)
+
+	return ok
+}
+
+func (c *Client) createUhid(ctx context.Context, id, vendorID, productID uint16, name string, desc []byte, dev *uhidDevice) error {
+	if err := c.registerUhid(id, dev); err != nil {
+		return err
+	}
+
+	if err := c.UhidCreateCtx(ctx, id, vendorID, productID, name, desc); err != nil {
+		c.unregisterUhid(id)
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) destroyUhid(id uint16) error {
+	if !c.unregisterUhid(id) {
+		return nil
+	}
+
+	ctx, cancel := c.releaseContext()
+	defer cancel()
+
+	if err := c.UhidDestroyCtx(ctx, id); err != nil && !errors.Is(err, ErrClosed) {
+		return fmt.Errorf("uhid destroy %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func (c *Client) destroyAllUhid() error {
+	c.uhidMutex.Lock()
+	devices := maps.Clone(c.uhidDevices)
+	c.uhidMutex.Unlock()
+
+	var errs []error
+
+	for _, id := range slices.Sorted(maps.Keys(devices)) {
+		if dev := devices[id]; dev.markClosed != nil {
+			dev.markClosed()
+		}
+
+		if err := c.destroyUhid(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) notifyUhidOutput(id uint16, data []byte) {
+	c.uhidMutex.Lock()
+	dev := c.uhidDevices[id]
+	c.uhidMutex.Unlock()
+
+	if dev != nil && dev.output != nil {
+		dev.output(data)
+	}
+}
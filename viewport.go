@@ -0,0 +1,184 @@
+package scrcpy
+
+import "math"
+
+type Viewport struct {
+	SurfaceWidth  float64
+	SurfaceHeight float64
+	PixelAspect   float64
+	FrameWidth    float64
+	FrameHeight   float64
+	Rotation      int
+	Zoom          float64
+	PanX          float64
+	PanY          float64
+	DeviceWidth   float64
+	DeviceHeight  float64
+	Crop          Crop
+}
+
+func NewViewport(surfaceWidth, surfaceHeight, frameWidth, frameHeight float64) *Viewport {
+	return &Viewport{
+		SurfaceWidth:  surfaceWidth,
+		SurfaceHeight: surfaceHeight,
+		PixelAspect:   1,
+		FrameWidth:    frameWidth,
+		FrameHeight:   frameHeight,
+		Zoom:          1,
+	}
+}
+
+func (v *Viewport) SetSurfaceSize(width, height float64) {
+	v.SurfaceWidth, v.SurfaceHeight = width, height
+}
+
+func (v *Viewport) SetFrameSize(width, height float64) {
+	v.FrameWidth, v.FrameHeight = width, height
+}
+
+func (v *Viewport) ToFrame(x, y float64) (uint32, uint32, bool) {
+	if !v.valid() {
+		return 0, 0, false
+	}
+
+	aspect := v.aspect()
+	scale := v.scale()
+	dx := (x*aspect - v.SurfaceWidth*aspect/2) / scale
+	dy := (y - v.SurfaceHeight/2) / scale
+
+	for range v.turns() {
+		dx, dy = dy, -dx
+	}
+
+	fx := v.FrameWidth/2 + v.PanX + dx
+	fy := v.FrameHeight/2 + v.PanY + dy
+
+	if fx < 0 || fy < 0 || fx >= v.FrameWidth || fy >= v.FrameHeight {
+		return 0, 0, false
+	}
+
+	return uint32(fx), uint32(fy), true
+}
+
+func (v *Viewport) ToSurface(fx, fy float64) (float64, float64) {
+	if !v.valid() {
+		return 0, 0
+	}
+
+	dx := fx - v.FrameWidth/2 - v.PanX
+	dy := fy - v.FrameHeight/2 - v.PanY
+
+	for range v.turns() {
+		dx, dy = -dy, dx
+	}
+
+	scale := v.scale()
+
+	return (v.SurfaceWidth*v.aspect()/2 + dx*scale) / v.aspect(), v.SurfaceHeight/2 + dy*scale
+}
+
+func (v *Viewport) DisplayRect() (x, y, w, h float64) {
+	x0, y0 := v.ToSurface(0, 0)
+	x1, y1 := v.ToSurface(v.FrameWidth, v.FrameHeight)
+
+	return math.Min(x0, x1), math.Min(y0, y1), math.Abs(x1 - x0), math.Abs(y1 - y0)
+}
+
+func (v *Viewport) FrameToDevice(fx, fy float64) (float64, float64) {
+	x, y, w, h := v.deviceRegion()
+
+	return x + fx*w/v.FrameWidth, y + fy*h/v.FrameHeight
+}
+
+func (v *Viewport) DeviceToFrame(dx, dy float64) (uint32, uint32, bool) {
+	x, y, w, h := v.deviceRegion()
+
+	fx := (dx - x) * v.FrameWidth / w
+	fy := (dy - y) * v.FrameHeight / h
+
+	if fx < 0 || fy < 0 || fx >= v.FrameWidth || fy >= v.FrameHeight {
+		return 0, 0, false
+	}
+
+	return uint32(fx), uint32(fy), true
+}
+
+func (v *Viewport) deviceRegion() (x, y, w, h float64) {
+	if v.Crop != (Crop{}) {
+		return float64(v.Crop.X), float64(v.Crop.Y), float64(v.Crop.Width), float64(v.Crop.Height)
+	}
+
+	if v.DeviceWidth > 0 && v.DeviceHeight > 0 {
+		return 0, 0, v.DeviceWidth, v.DeviceHeight
+	}
+
+	return 0, 0, v.FrameWidth, v.FrameHeight
+}
+
+func (v *Viewport) valid() bool {
+	return v.SurfaceWidth > 0 && v.SurfaceHeight > 0 && v.FrameWidth > 0 && v.FrameHeight > 0
+}
+
+func (v *Viewport) turns() int {
+	return (v.Rotation%4 + 4) % 4
+}
+
+func (v *Viewport) aspect() float64 {
+	if v.PixelAspect <= 0 {
+		return 1
+	}
+
+	return v.PixelAspect
+}
+
+func (v *Viewport) scale() float64 {
+	w, h := v.FrameWidth, v.FrameHeight
+	if v.turns()%2 == 1 {
+		w, h = h, w
+	}
+
+	scale := math.Min(v.SurfaceWidth*v.aspect()/w, v.SurfaceHeight/h)
+	if v.Zoom > 0 {
+		scale *= v.Zoom
+	}
+
+	return scale
+}
+
+func VideoSize(width, height uint32, crop Crop, maxSize uint16) (uint32, uint32) {
+	if crop != (Crop{}) {
+		width, height = crop.Width, crop.Height
+	}
+
+	limit := uint32(maxSize) &^ 7
+
+	portrait := height > width
+	major, minor := width, height
+	if portrait {
+		major, minor = height, width
+	}
+
+	if limit > 0 && major > limit {
+		minor = uint32(uint64(limit) * uint64(minor) / uint64(major))
+		major = limit
+	}
+
+	major, minor = round8(major, minor)
+
+	if portrait {
+		return minor, major
+	}
+
+	return major, minor
+}
+
+func round8(major, minor uint32) (uint32, uint32) {
+	if major%8 == 0 && minor%8 == 0 {
+		return major, minor
+	}
+
+	major &^= 7
+	minor = min((minor+4)&^7, major)
+
+	return major, minor
+}
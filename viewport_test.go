@@ -0,0 +1,41 @@
+package scrcpy_test
+
+import (
+	"testing"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+)
+
+func TestVideoSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height uint32
+		crop          scrcpy.Crop
+		maxSize       uint16
+		wantW, wantH  uint32
+	}{
+		{name: "aligned", width: 1080, height: 1920, wantW: 1080, wantH: 1920},
+		{name: "major rounds down", width: 1080, height: 2340, wantW: 1080, wantH: 2336},
+		{name: "landscape major rounds down", width: 1084, height: 600, wantW: 1080, wantH: 600},
+		{name: "minor rounds to nearest", width: 1084, height: 1920, wantW: 1088, wantH: 1920},
+		{name: "minor rounds down to nearest", width: 1083, height: 1920, wantW: 1080, wantH: 1920},
+		{name: "limit portrait", width: 1080, height: 2400, maxSize: 1024, wantW: 464, wantH: 1024},
+		{name: "limit landscape", width: 2400, height: 1080, maxSize: 1024, wantW: 1024, wantH: 464},
+		{name: "limit cleared to multiple of 8", width: 1080, height: 2400, maxSize: 1023, wantW: 456, wantH: 1016},
+		{name: "limit above size", width: 1080, height: 1920, maxSize: 2048, wantW: 1080, wantH: 1920},
+		{name: "limit 1920", width: 1440, height: 3120, maxSize: 1920, wantW: 888, wantH: 1920},
+		{name: "square", width: 1000, height: 1000, maxSize: 720, wantW: 720, wantH: 720},
+		{name: "minor capped by major", width: 7, height: 12, wantW: 8, wantH: 8},
+		{name: "crop", width: 1080, height: 2400, crop: scrcpy.Crop{Width: 1000, Height: 1003, X: 10, Y: 20}, wantW: 1000, wantH: 1000},
+		{name: "crop and limit", width: 1080, height: 2400, crop: scrcpy.Crop{Width: 1080, Height: 1350}, maxSize: 800, wantW: 640, wantH: 800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := scrcpy.VideoSize(tt.width, tt.height, tt.crop, tt.maxSize)
+			if w != tt.wantW || h != tt.wantH {
+				t.Fatalf("VideoSize(%d, %d, %+v, %d) = %dx%d, want %dx%d", tt.width, tt.height, tt.crop, tt.maxSize, w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
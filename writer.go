@@ -0,0 +1,174 @@
+package scrcpy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const writeQueueLen = 64
+
+type writeRequest struct {
+	ctx     context.Context
+	data    []byte
+	onWrite func()
+	result  chan error
+}
+
+type controlWriter struct {
+	conn    net.Conn
+	timeout time.Duration
+	batch   int
+	queue   chan *writeRequest
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func newControlWriter(conn net.Conn, timeout time.Duration, batch int) *controlWriter {
+	w := &controlWriter{
+		conn:    conn,
+		timeout: timeout,
+		batch:   max(batch, 1),
+		queue:   make(chan *writeRequest, writeQueueLen),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+func (w *controlWriter) send(ctx context.Context, data []byte, onWrite func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	req := &writeRequest{
+		ctx:     ctx,
+		data:    data,
+		onWrite: onWrite,
+		result:  make(chan error, 1),
+	}
+
+	select {
+	case w.queue <- req:
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-w.stopped:
+		select {
+		case err := <-req.result:
+			return err
+		default:
+			return ErrClosed
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *controlWriter) close() {
+	w.once.Do(func() { close(w.done) })
+	<-w.stopped
+}
+
+func (w *controlWriter) run() {
+	defer close(w.stopped)
+
+	for {
+		select {
+		case <-w.done:
+			w.drain()
+
+			return
+		case req := <-w.queue:
+			batch := w.collect(req)
+
+			if err := w.flush(batch); err != nil {
+				w.once.Do(func() { close(w.done) })
+			}
+		}
+	}
+}
+
+func (w *controlWriter) collect(first *writeRequest) []*writeRequest {
+	batch := []*writeRequest{first}
+
+	for len(batch) < w.batch {
+		select {
+		case req := <-w.queue:
+			batch = append(batch, req)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+func (w *controlWriter) flush(batch []*writeRequest) error {
+	var (
+		buf      []byte
+		pending  []*writeRequest
+		deadline time.Time
+	)
+
+	if w.timeout > 0 {
+		deadline = time.Now().Add(w.timeout)
+	}
+
+	for _, req := range batch {
+		if err := req.ctx.Err(); err != nil {
+			req.result <- err
+
+			continue
+		}
+
+		buf = append(buf, req.data...)
+		pending = append(pending, req)
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	for _, req := range pending {
+		if req.onWrite != nil {
+			req.onWrite()
+		}
+	}
+
+	_ = w.conn.SetWriteDeadline(deadline)
+
+	_, err := w.conn.Write(buf)
+	if err != nil {
+		err = fmt.Errorf("control write: %w", err)
+	}
+
+	for _, req := range pending {
+		req.result <- err
+	}
+
+	return err
+}
+
+func (w *controlWriter) drain() {
+	for {
+		select {
+		case req := <-w.queue:
+			req.result <- ErrClosed
+		default:
+			return
+		}
+	}
+}
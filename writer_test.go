@@ -0,0 +1,106 @@
+package scrcpy_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	scrcpy "github.com/merzzzl/scrcpy-go"
+	"github.com/merzzzl/scrcpy-go/protocol"
+	"github.com/merzzzl/scrcpy-go/scrcpytest"
+)
+
+func TestConcurrentSend(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig(), scrcpy.WithWriteBatch(4))
+	serveTest(t, c)
+
+	const (
+		senders = 16
+		each    = 32
+	)
+
+	ctx := testContext(t)
+
+	var wg sync.WaitGroup
+
+	for i := range senders {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range each {
+				if err := c.InjectTextCtx(ctx, fmt.Sprintf("%02d-%02d", i, j)); err != nil {
+					t.Errorf("InjectText: %v", err)
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	msgs, err := srv.WaitMessages(ctx, senders*each)
+	if err != nil {
+		t.Fatalf("WaitMessages: %v", err)
+	}
+
+	if len(msgs) != senders*each {
+		t.Fatalf("got %d messages, want %d", len(msgs), senders*each)
+	}
+
+	next := make([]int, senders)
+
+	for _, msg := range msgs {
+		m, ok := msg.(*protocol.InjectText)
+		if !ok {
+			t.Fatalf("unexpected message %v", msg)
+		}
+
+		var i, j int
+		if _, err := fmt.Sscanf(m.Text, "%d-%d", &i, &j); err != nil {
+			t.Fatalf("corrupt text %q", m.Text)
+		}
+
+		if j != next[i] {
+			t.Fatalf("sender %d: got %d, want %d", i, j, next[i])
+		}
+
+		next[i]++
+	}
+}
+
+func TestSendContext(t *testing.T) {
+	srv, c := dialTest(t, scrcpytest.DefaultConfig())
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.InjectTextCtx(cancelled, "lost"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("InjectText = %v, want context.Canceled", err)
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_ = c.InjectTextCtx(short, "maybe")
+
+	ctx := testContext(t)
+
+	if err := c.InjectTextCtx(ctx, "kept"); err != nil {
+		t.Fatalf("InjectText after cancelled send: %v", err)
+	}
+
+	msgs, err := srv.WaitMessages(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m, ok := msgs[len(msgs)-1].(*protocol.InjectText); !ok || m.Text != "kept" {
+		t.Fatalf("last message = %v", msgs[len(msgs)-1])
+	}
+}